@@ -0,0 +1,216 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultFinalAnswerTemplate = `Answer the following input as best you can using the results of the steps
+that have already been taken. No more tools can be used.
+
+Input: {{.input}}
+
+Steps taken:
+{{.intermediate_steps}}
+
+Final Answer:`
+
+// Budget limits the resources a single executor run may use. Zero values mean no limit.
+// The budget is checked before every planning step and every tool call, so a run can
+// exceed it by at most one llm or tool call.
+type Budget struct {
+	// MaxTokens is the max number of tokens the llm calls of the run may use, as
+	// reported in the generation info of the llm responses.
+	MaxTokens int
+	// MaxDuration is the max wall-clock time of the run.
+	MaxDuration time.Duration
+	// MaxToolCalls is the max number of tool calls of the run.
+	MaxToolCalls int
+}
+
+// runBudget tracks the resources used by a single executor run.
+type runBudget struct {
+	Budget
+	start     time.Time
+	tokens    *tokenCounter
+	toolCalls int
+}
+
+func newRunBudget(ctx context.Context, budget Budget) (context.Context, *runBudget) {
+	parent, _ := ctx.Value(tokenCounterKey{}).(*tokenCounter)
+	tokens := &tokenCounter{parent: parent}
+
+	return context.WithValue(ctx, tokenCounterKey{}, tokens), &runBudget{
+		Budget: budget,
+		start:  time.Now(),
+		tokens: tokens,
+	}
+}
+
+// check returns ErrBudgetExceeded if any limit of the budget is reached.
+func (b *runBudget) check() error {
+	if b.MaxTokens > 0 && b.tokens.total.Load() >= int64(b.MaxTokens) {
+		return fmt.Errorf("%w: used %d of %d tokens", ErrBudgetExceeded, b.tokens.total.Load(), b.MaxTokens)
+	}
+	if b.MaxDuration > 0 && time.Since(b.start) >= b.MaxDuration {
+		return fmt.Errorf("%w: ran for more than %s", ErrBudgetExceeded, b.MaxDuration)
+	}
+	return nil
+}
+
+// useToolCall checks the budget and counts a tool call.
+func (b *runBudget) useToolCall() error {
+	if err := b.check(); err != nil {
+		return err
+	}
+	if b.MaxToolCalls > 0 && b.toolCalls >= b.MaxToolCalls {
+		return fmt.Errorf("%w: used %d of %d tool calls", ErrBudgetExceeded, b.toolCalls, b.MaxToolCalls)
+	}
+	b.toolCalls++
+	return nil
+}
+
+type tokenCounterKey struct{}
+
+// tokenCounter counts the tokens used by an executor run. Counters of nested runs,
+// such as runs of workers of a supervisor, also count towards their parents.
+type tokenCounter struct {
+	total  atomic.Int64
+	parent *tokenCounter
+}
+
+// recordTokenUsage adds the tokens reported in the response to the token counters
+// of the executor runs the context belongs to.
+func recordTokenUsage(ctx context.Context, resp *llms.ContentResponse) {
+	counter, ok := ctx.Value(tokenCounterKey{}).(*tokenCounter)
+	if !ok || resp == nil {
+		return
+	}
+
+	tokens := responseTokens(resp)
+	for ; counter != nil; counter = counter.parent {
+		counter.total.Add(int64(tokens))
+	}
+}
+
+// responseTokens returns the number of tokens used by a response. The usage is the
+// same for every choice, so it is taken from the first choice reporting it.
+func responseTokens(resp *llms.ContentResponse) int {
+	keys := [][]string{
+		{"TotalTokens"},
+		{"total_tokens"},
+		{"PromptTokens", "CompletionTokens"},
+		{"InputTokens", "OutputTokens"},
+		{"input_tokens", "output_tokens"},
+	}
+
+	for _, choice := range resp.Choices {
+		for _, group := range keys {
+			total, found := 0, false
+			for _, key := range group {
+				if n, ok := toInt(choice.GenerationInfo[key]); ok {
+					total += n
+					found = true
+				}
+			}
+			if found {
+				return total
+			}
+		}
+	}
+
+	return 0
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
+// usageTrackingModel is an llm that records the token usage of its responses for
+// the budget of the executor run it is called in.
+type usageTrackingModel struct {
+	llms.Model
+}
+
+func (m usageTrackingModel) GenerateContent(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	resp, err := m.Model.GenerateContent(ctx, messages, options...)
+	recordTokenUsage(ctx, resp)
+	return resp, err
+}
+
+func (m usageTrackingModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// forceFinalAnswer asks the final answer llm of the executor to answer the input
+// from the steps taken so far, for runs that were stopped before the agent finished.
+func (e *Executor) forceFinalAnswer(
+	ctx context.Context,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+) (*schema.AgentFinish, error) {
+	prompt := e.FinalAnswerPrompt
+	if prompt.Template == "" {
+		prompt = prompts.NewPromptTemplate(_defaultFinalAnswerTemplate, []string{"input", "intermediate_steps"})
+	}
+
+	values := make(map[string]any, len(inputs)+1)
+	for key, value := range inputs {
+		values[key] = value
+	}
+	values["intermediate_steps"] = formatSteps(steps)
+
+	text, err := prompt.Format(values)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := llms.GenerateFromSinglePrompt(ctx, usageTrackingModel{e.FinalAnswerLLM}, text)
+	if err != nil {
+		return nil, err
+	}
+
+	outputKey := _defaultOutputKey
+	if outputKeys := e.Agent.GetOutputKeys(); len(outputKeys) > 0 {
+		outputKey = outputKeys[0]
+	}
+
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{outputKey: strings.TrimSpace(output)},
+		Log:          output,
+	}, nil
+}
+
+func formatSteps(steps []schema.AgentStep) string {
+	var sb strings.Builder
+	for _, step := range steps {
+		if step.Action.Tool != "" {
+			fmt.Fprintf(&sb, "Action: %s\nAction Input: %s\n", step.Action.Tool, step.Action.ToolInput)
+		}
+		fmt.Fprintf(&sb, "Observation: %s\n", step.Observation)
+	}
+
+	return sb.String()
+}
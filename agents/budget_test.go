@@ -0,0 +1,68 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestExecutorBudget(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		option agents.Option
+		steps  int
+	}{
+		{name: "tokens", option: agents.WithTokenBudget(25), steps: 2},
+		{name: "tool calls", option: agents.WithToolCallBudget(2), steps: 2},
+		{name: "time", option: agents.WithTimeBudget(time.Nanosecond), steps: 0},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			llm := &scriptedLLM{
+				responses: []string{"Action: calculator\nAction Input: 1+1"},
+				tokens:    10,
+			}
+			executor := agents.NewExecutor(
+				agents.NewOneShotAgent(llm, []tools.Tool{tools.Calculator{}}),
+				agents.WithMaxIterations(10),
+				agents.WithReturnIntermediateSteps(),
+				tc.option,
+			)
+
+			result, err := chains.Call(context.Background(), executor, map[string]any{"input": "1+1"})
+			require.ErrorIs(t, err, agents.ErrBudgetExceeded)
+			require.Len(t, result["intermediateSteps"], tc.steps)
+		})
+	}
+}
+
+func TestExecutorForcedFinalAnswer(t *testing.T) {
+	t.Parallel()
+
+	finalLLM := &scriptedLLM{responses: []string{" two "}}
+	executor := agents.NewExecutor(
+		agents.NewOneShotAgent(
+			&scriptedLLM{responses: []string{"Action: calculator\nAction Input: 1+1"}},
+			[]tools.Tool{tools.Calculator{}},
+		),
+		agents.WithMaxIterations(3),
+		agents.WithToolCallBudget(1),
+		agents.WithFinalAnswerLLM(finalLLM),
+	)
+
+	result, err := chains.Run(context.Background(), executor, "what is 1+1?")
+	require.NoError(t, err)
+	require.Equal(t, "two", result)
+	require.Equal(t, 1, finalLLM.calls)
+}
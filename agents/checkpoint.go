@@ -0,0 +1,182 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrCheckpointNotFound is returned by a CheckpointStore if no checkpoint exists for a run ID.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint is the persisted state of an executor run. It is saved after every
+// iteration of the executor so that an interrupted run can be resumed without
+// repeating the tool calls that already completed.
+type Checkpoint struct {
+	// RunID identifies the run the checkpoint belongs to.
+	RunID string `json:"run_id"`
+	// Inputs are the inputs the run was started with, excluding memory variables.
+	Inputs map[string]string `json:"inputs"`
+	// Steps are the intermediate steps taken so far.
+	Steps []schema.AgentStep `json:"steps"`
+	// Iterations is the number of executor iterations completed so far.
+	Iterations int `json:"iterations"`
+	// UpdatedAt is the time the checkpoint was last saved.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore is the interface for persisting executor checkpoints.
+type CheckpointStore interface {
+	// Save creates or replaces the checkpoint for the run ID of the checkpoint.
+	Save(ctx context.Context, checkpoint Checkpoint) error
+	// Load returns the checkpoint for a run ID. If no checkpoint exists
+	// ErrCheckpointNotFound is returned.
+	Load(ctx context.Context, runID string) (*Checkpoint, error)
+	// Delete removes the checkpoint for a run ID. Deleting a checkpoint that
+	// does not exist is not an error.
+	Delete(ctx context.Context, runID string) error
+}
+
+type runIDContextKey struct{}
+
+// ContextWithRunID returns a copy of ctx carrying a run ID. When an executor with
+// a checkpoint store is called with such a context, its progress is checkpointed
+// under the run ID, and an existing checkpoint for the run ID is resumed.
+func ContextWithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDContextKey{}, runID)
+}
+
+// RunIDFromContext returns the run ID set with ContextWithRunID, if any.
+func RunIDFromContext(ctx context.Context) (string, bool) {
+	runID, ok := ctx.Value(runIDContextKey{}).(string)
+	return runID, ok && runID != ""
+}
+
+// InMemoryCheckpointStore is a CheckpointStore that keeps checkpoints in memory.
+// It is safe for concurrent use.
+type InMemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]Checkpoint
+}
+
+var _ CheckpointStore = &InMemoryCheckpointStore{}
+
+// NewInMemoryCheckpointStore creates a new in-memory checkpoint store.
+func NewInMemoryCheckpointStore() *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+// Save stores a copy of the checkpoint.
+func (s *InMemoryCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint.Steps = append([]schema.AgentStep(nil), checkpoint.Steps...)
+	s.checkpoints[checkpoint.RunID] = checkpoint
+	return nil
+}
+
+// Load returns a copy of the checkpoint for the run ID.
+func (s *InMemoryCheckpointStore) Load(_ context.Context, runID string) (*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoint, ok := s.checkpoints[runID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runID)
+	}
+	checkpoint.Steps = append([]schema.AgentStep(nil), checkpoint.Steps...)
+	return &checkpoint, nil
+}
+
+// Delete removes the checkpoint for the run ID.
+func (s *InMemoryCheckpointStore) Delete(_ context.Context, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checkpoints, runID)
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore that writes each checkpoint as a JSON
+// file named after its run ID into a directory.
+type FileCheckpointStore struct {
+	// Dir is the directory the checkpoint files are written to.
+	Dir string
+}
+
+var _ CheckpointStore = FileCheckpointStore{}
+
+// NewFileCheckpointStore creates a new file checkpoint store writing to dir. The
+// directory is created if it does not exist.
+func NewFileCheckpointStore(dir string) (FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return FileCheckpointStore{}, err
+	}
+	return FileCheckpointStore{Dir: dir}, nil
+}
+
+// Save writes the checkpoint to its file. The file is replaced atomically so a
+// crash while saving leaves the previous checkpoint intact.
+func (s FileCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(checkpoint.RunID))
+}
+
+// Load reads the checkpoint for the run ID from its file.
+func (s FileCheckpointStore) Load(_ context.Context, runID string) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Delete removes the checkpoint file for the run ID.
+func (s FileCheckpointStore) Delete(_ context.Context, runID string) error {
+	err := os.Remove(s.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s FileCheckpointStore) path(runID string) string {
+	return filepath.Join(s.Dir, url.PathEscape(runID)+".json")
+}
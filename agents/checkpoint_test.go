@@ -0,0 +1,104 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+var errToolCrashed = errors.New("tool crashed")
+
+type countingTool struct {
+	calls  int
+	failAt int
+}
+
+func (t *countingTool) Name() string        { return "counter" }
+func (t *countingTool) Description() string { return "counts its calls" }
+
+func (t *countingTool) Call(_ context.Context, input string) (string, error) {
+	t.calls++
+	if t.calls == t.failAt {
+		return "", errToolCrashed
+	}
+	return input + " done", nil
+}
+
+// stepAgent calls its tool once per step until it has seen the given number of
+// steps, then finishes.
+type stepAgent struct {
+	tool  tools.Tool
+	steps int
+
+	recordedIntermediateSteps []schema.AgentStep
+}
+
+func (a *stepAgent) Plan(
+	_ context.Context,
+	intermediateSteps []schema.AgentStep,
+	_ map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.recordedIntermediateSteps = intermediateSteps
+	if len(intermediateSteps) >= a.steps {
+		return nil, &schema.AgentFinish{ReturnValues: map[string]any{"output": "finished"}}, nil
+	}
+	return []schema.AgentAction{{Tool: a.tool.Name(), ToolInput: "step"}}, nil, nil
+}
+
+func (a *stepAgent) GetInputKeys() []string  { return []string{"input"} }
+func (a *stepAgent) GetOutputKeys() []string { return []string{"output"} }
+func (a *stepAgent) GetTools() []tools.Tool  { return []tools.Tool{a.tool} }
+
+func TestExecutorCheckpointResume(t *testing.T) {
+	t.Parallel()
+
+	fileStore, err := agents.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	for name, store := range map[string]agents.CheckpointStore{
+		"memory": agents.NewInMemoryCheckpointStore(),
+		"file":   fileStore,
+	} {
+		store := store
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := agents.ContextWithRunID(context.Background(), "run-1")
+			tool := &countingTool{failAt: 3}
+			a := &stepAgent{tool: tool, steps: 3}
+			executor := agents.NewExecutor(a, agents.WithCheckpointStore(store))
+
+			_, err := chains.Call(ctx, executor, map[string]any{"input": "foo"})
+			require.ErrorIs(t, err, errToolCrashed)
+
+			checkpoint, err := store.Load(ctx, "run-1")
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"input": "foo"}, checkpoint.Inputs)
+			require.Len(t, checkpoint.Steps, 2)
+			require.Equal(t, 2, checkpoint.Iterations)
+
+			result, err := executor.Resume(context.Background(), "run-1")
+			require.NoError(t, err)
+			require.Equal(t, "finished", result["output"])
+			require.Equal(t, 4, tool.calls)
+			require.Len(t, a.recordedIntermediateSteps, 3)
+
+			_, err = store.Load(ctx, "run-1")
+			require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+		})
+	}
+}
+
+func TestExecutorResumeWithoutStore(t *testing.T) {
+	t.Parallel()
+
+	executor := agents.NewExecutor(&stepAgent{tool: &countingTool{}})
+	_, err := executor.Resume(context.Background(), "run-1")
+	require.ErrorIs(t, err, agents.ErrNoCheckpointStore)
+}
@@ -48,7 +48,7 @@ func NewConversationalAgent(llm llms.Model, tools []tools.Tool, opts ...Option)
 
 	return &ConversationalAgent{
 		Chain: chains.NewLLMChain(
-			llm,
+			usageTrackingModel{llm},
 			options.getConversationalPrompt(tools),
 			chains.WithCallback(options.callbacksHandler),
 		),
@@ -71,14 +71,7 @@ func (a *ConversationalAgent) Plan(
 
 	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, a.CallbacksHandler)
 
 	output, err := chains.Predict(
 		ctx,
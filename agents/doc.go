@@ -19,4 +19,9 @@
 // calling the tool that the action references with the corresponding input,
 // getting the output of the tool, and then passing all that information back
 // into the Agent to get the next action it should take.
+//
+// Executor.Stream runs the executor in the background and returns a channel of
+// typed events (llm token deltas, planned actions, tool starts and ends with
+// their observations, and the final outputs), for callers that want to show the
+// progress of an agent while it runs.
 package agents
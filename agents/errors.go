@@ -10,10 +10,18 @@ var (
 	// ErrNotFinished is returned if the agent does not give a finish before  the number of iterations
 	// is larger than max iterations.
 	ErrNotFinished = errors.New("agent not finished before max iterations")
+	// ErrBudgetExceeded is returned if a run exhausts the budget of the executor before the
+	// agent gives a finish.
+	ErrBudgetExceeded = errors.New("agent not finished before budget was exhausted")
 	// ErrUnknownAgentType is returned if the type given to the initializer is invalid.
 	ErrUnknownAgentType = errors.New("unknown agent type")
 	// ErrInvalidOptions is returned if the options given to the initializer is invalid.
 	ErrInvalidOptions = errors.New("invalid options")
+	// ErrMaxDelegationDepth is returned if a call delegated through a ChainTool is nested deeper
+	// than the max delegation depth of the tool.
+	ErrMaxDelegationDepth = errors.New("max delegation depth exceeded")
+	// ErrNoCheckpointStore is returned if a run is resumed on an executor without a checkpoint store.
+	ErrNoCheckpointStore = errors.New("executor has no checkpoint store")
 
 	// ErrUnableToParseOutput is returned if the output of the llm is unparsable.
 	ErrUnableToParseOutput = errors.New("unable to parse agent output")
@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EventType is the type of an event emitted by a streamed executor run.
+type EventType string
+
+const (
+	// EventLLMToken is emitted for every chunk the agent's llm streams while planning.
+	EventLLMToken EventType = "llm_token"
+	// EventAction is emitted when the agent has planned an action.
+	EventAction EventType = "action"
+	// EventToolStart is emitted before the tool of an action is called.
+	EventToolStart EventType = "tool_start"
+	// EventToolEnd is emitted with the observation returned by a tool.
+	EventToolEnd EventType = "tool_end"
+	// EventInvalidTool is emitted instead of EventToolStart and EventToolEnd when
+	// an action names a tool the agent does not have. It holds the observation
+	// given to the agent.
+	EventInvalidTool EventType = "invalid_tool"
+	// EventFinish is the last event of a successful run and holds its outputs.
+	EventFinish EventType = "finish"
+	// EventError is the last event of a failed run and holds the error.
+	EventError EventType = "error"
+)
+
+// Event is an event emitted by a streamed executor run. Which fields are set
+// depends on the type of the event.
+type Event struct {
+	Type EventType
+	// Agent is the name of the worker the event was emitted by, if the event was
+	// emitted in a call delegated through a ChainTool. See DelegationPath.
+	Agent string
+	// Token is the streamed chunk of an EventLLMToken event.
+	Token string
+	// Action is the action of EventAction, EventToolStart, EventToolEnd and
+	// EventInvalidTool events.
+	Action *schema.AgentAction
+	// Observation is the tool output of an EventToolEnd event, or the observation
+	// of an EventInvalidTool event.
+	Observation string
+	// Outputs are the outputs of the executor for EventFinish and EventError events.
+	Outputs map[string]any
+	// Err is the error of an EventError event.
+	Err error
+}
+
+type eventSinkKey struct{}
+
+// Stream runs the executor in the background and returns a channel of the events of
+// the run. The executor is called through chains.Call, so memory and callbacks work
+// as usual. The channel is closed after an EventFinish or EventError event. Callers
+// that stop reading before that must cancel ctx to end the run.
+func (e *Executor) Stream(
+	ctx context.Context,
+	inputValues map[string]any,
+	options ...chains.ChainCallOption,
+) <-chan Event {
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+
+		sinkCtx := context.WithValue(ctx, eventSinkKey{}, func(event Event) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		})
+
+		outputs, err := chains.Call(sinkCtx, e, inputValues, options...)
+		if err != nil {
+			emitEvent(sinkCtx, Event{Type: EventError, Outputs: outputs, Err: err})
+			return
+		}
+		emitEvent(sinkCtx, Event{Type: EventFinish, Outputs: outputs})
+	}()
+
+	return events
+}
+
+// emitEvent sends the event to the stream the context belongs to, if any.
+func emitEvent(ctx context.Context, event Event) {
+	if sink, ok := ctx.Value(eventSinkKey{}).(func(Event)); ok {
+		if path := DelegationPath(ctx); len(path) > 0 && event.Agent == "" {
+			event.Agent = path[len(path)-1]
+		}
+		sink(event)
+	}
+}
+
+// streamingFunc returns the streaming function an agent should give its llm. Chunks
+// are passed to the callbacks handler and emitted as events of a streamed run. If
+// neither is present no streaming function is returned.
+func streamingFunc(ctx context.Context, handler callbacks.Handler) func(context.Context, []byte) error {
+	_, streamed := ctx.Value(eventSinkKey{}).(func(Event))
+	if handler == nil && !streamed {
+		return nil
+	}
+
+	return func(chunkCtx context.Context, chunk []byte) error {
+		if handler != nil {
+			handler.HandleStreamingFunc(chunkCtx, chunk)
+		}
+		emitEvent(ctx, Event{Type: EventLLMToken, Token: string(chunk)})
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+package agents_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// scriptedLLM returns its responses in order, streaming each of them word by word.
+type scriptedLLM struct {
+	responses []string
+	calls     int
+	// tokens is the number of tokens every response reports as used.
+	tokens int
+}
+
+func (l *scriptedLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l *scriptedLLM) GenerateContent(
+	ctx context.Context,
+	_ []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	response := l.responses[l.calls%len(l.responses)]
+	l.calls++
+
+	if opts.StreamingFunc != nil {
+		for _, word := range strings.SplitAfter(response, " ") {
+			if err := opts.StreamingFunc(ctx, []byte(word)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content:        response,
+		GenerationInfo: map[string]any{"TotalTokens": l.tokens},
+	}}}, nil
+}
+
+func TestExecutorStream(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{responses: []string{
+		"I should calculate.\nAction: calculator\nAction Input: 1+1",
+		"I know it.\nFinal Answer: 2",
+	}}
+	executor := agents.NewExecutor(agents.NewOneShotAgent(llm, []tools.Tool{tools.Calculator{}}))
+
+	var tokens strings.Builder
+	var types []agents.EventType
+	var last agents.Event
+	for event := range executor.Stream(context.Background(), map[string]any{"input": "what is 1+1?"}) {
+		if event.Type == agents.EventLLMToken {
+			tokens.WriteString(event.Token)
+			continue
+		}
+		types = append(types, event.Type)
+		if event.Type == agents.EventToolEnd {
+			require.Equal(t, "calculator", event.Action.Tool)
+			require.Equal(t, "2", event.Observation)
+		}
+		last = event
+	}
+
+	require.Equal(t, []agents.EventType{
+		agents.EventAction,
+		agents.EventToolStart,
+		agents.EventToolEnd,
+		agents.EventFinish,
+	}, types)
+	require.Equal(t, strings.Join(llm.responses, ""), tokens.String())
+	require.Equal(t, " 2", last.Outputs["output"])
+}
+
+func TestExecutorStreamInvalidTool(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{responses: []string{
+		"Action: search\nAction Input: 1+1",
+		"Final Answer: 2",
+	}}
+	executor := agents.NewExecutor(agents.NewOneShotAgent(llm, []tools.Tool{tools.Calculator{}}))
+
+	var types []agents.EventType
+	for event := range executor.Stream(context.Background(), map[string]any{"input": "what is 1+1?"}) {
+		if event.Type == agents.EventLLMToken {
+			continue
+		}
+		types = append(types, event.Type)
+		if event.Type == agents.EventInvalidTool {
+			require.Equal(t, "search", event.Action.Tool)
+			require.Equal(t, "search is not a valid tool, try another one", event.Observation)
+		}
+	}
+
+	require.Equal(t, []agents.EventType{
+		agents.EventAction,
+		agents.EventInvalidTool,
+		agents.EventFinish,
+	}, types)
+}
+
+func TestExecutorStreamError(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{responses: []string{"Action: calculator\nAction Input: 1+1"}}
+	executor := agents.NewExecutor(
+		agents.NewOneShotAgent(llm, []tools.Tool{tools.Calculator{}}),
+		agents.WithMaxIterations(1),
+	)
+
+	var last agents.Event
+	for event := range executor.Stream(context.Background(), map[string]any{"input": "what is 1+1?"}) {
+		last = event
+	}
+	require.Equal(t, agents.EventError, last.Type)
+	require.ErrorIs(t, last.Err, agents.ErrNotFinished)
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/tools"
 )
@@ -20,9 +23,18 @@ type Executor struct {
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
 	ErrorHandler     *ParserErrorHandler
+	CheckpointStore  CheckpointStore
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	// Budget limits the tokens, time and tool calls of a run.
+	Budget Budget
+	// FinalAnswerLLM is used to generate a final answer from the steps taken when a
+	// run exhausts its budget or max iterations. If nil, such runs return an error.
+	FinalAnswerLLM llms.Model
+	// FinalAnswerPrompt is the prompt used with FinalAnswerLLM. It is given the inputs
+	// of the run and the formatted steps as "intermediate_steps".
+	FinalAnswerPrompt prompts.PromptTemplate
 }
 
 var (
@@ -44,9 +56,18 @@ func NewExecutor(agent Agent, opts ...Option) *Executor {
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		CheckpointStore:         options.checkpointStore,
+		Budget:                  options.budget,
+		FinalAnswerLLM:          options.finalAnswerLLM,
+		FinalAnswerPrompt:       options.finalAnswerPrompt,
 	}
 }
 
+// Call runs the agent until it returns a finish, or the max number of iterations
+// or the budget of the executor is exhausted. If the executor has a checkpoint store
+// and ctx carries a run ID (see ContextWithRunID), the inputs and steps are
+// checkpointed after every iteration, and a run with an existing checkpoint
+// continues from the saved steps.
 func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
 	inputs, err := inputsToString(inputValues)
 	if err != nil {
@@ -54,24 +75,146 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 	}
 	nameToTool := getNameToTool(e.Agent.GetTools())
 
-	steps := make([]schema.AgentStep, 0)
-	for i := 0; i < e.MaxIterations; i++ {
+	checkpoint, err := e.loadCheckpoint(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, budget := newRunBudget(ctx, e.Budget)
+	steps := checkpoint.Steps
+	for i := checkpoint.Iterations; i < e.MaxIterations; i++ {
+		if err := budget.check(); err != nil {
+			return e.stopEarly(ctx, inputs, steps, checkpoint, err)
+		}
+
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
-		if finish != nil || err != nil {
+		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs, budget)
+		if errors.Is(err, ErrBudgetExceeded) {
+			return e.stopEarly(ctx, inputs, steps, checkpoint, err)
+		}
+		if err != nil {
 			return finish, err
 		}
+		if finish != nil {
+			return finish, e.deleteCheckpoint(ctx, checkpoint)
+		}
+
+		checkpoint.Steps = steps
+		checkpoint.Iterations = i + 1
+		if err := e.saveCheckpoint(ctx, checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.stopEarly(ctx, inputs, steps, checkpoint, ErrNotFinished)
+}
+
+// stopEarly ends a run that was stopped before the agent finished. If the executor
+// has a final answer llm, the run returns the answer it generates from the steps
+// taken. Otherwise the steps taken are returned along with the reason for stopping.
+func (e *Executor) stopEarly(
+	ctx context.Context,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+	checkpoint *Checkpoint,
+	reason error,
+) (map[string]any, error) {
+	if err := e.deleteCheckpoint(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+
+	if e.FinalAnswerLLM != nil {
+		finish, err := e.forceFinalAnswer(ctx, inputs, steps)
+		if err != nil {
+			return nil, err
+		}
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+		}
+		return e.getReturn(finish, steps), nil
 	}
 
 	if e.CallbacksHandler != nil {
 		e.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
-			ReturnValues: map[string]any{"output": ErrNotFinished.Error()},
+			ReturnValues: map[string]any{"output": reason.Error()},
 		})
 	}
 	return e.getReturn(
 		&schema.AgentFinish{ReturnValues: make(map[string]any)},
 		steps,
-	), ErrNotFinished
+	), reason
+}
+
+// Resume continues the run with the given run ID from its last checkpoint. The
+// inputs the run was started with are restored from the checkpoint, and the run
+// then proceeds as a normal call using chains.Call.
+func (e *Executor) Resume(ctx context.Context, runID string, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	if e.CheckpointStore == nil {
+		return nil, ErrNoCheckpointStore
+	}
+
+	checkpoint, err := e.CheckpointStore.Load(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputValues := make(map[string]any, len(checkpoint.Inputs))
+	for key, value := range checkpoint.Inputs {
+		inputValues[key] = value
+	}
+
+	return chains.Call(ContextWithRunID(ctx, runID), e, inputValues, options...)
+}
+
+// loadCheckpoint returns the checkpoint the run in ctx should start from. Runs that
+// are not checkpointed, or that have no checkpoint yet, start from an empty one.
+func (e *Executor) loadCheckpoint(ctx context.Context, inputs map[string]string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{Steps: make([]schema.AgentStep, 0)}
+
+	runID, ok := RunIDFromContext(ctx)
+	if e.CheckpointStore == nil || !ok {
+		return checkpoint, nil
+	}
+
+	stored, err := e.CheckpointStore.Load(ctx, runID)
+	if err == nil {
+		return stored, nil
+	}
+	if !errors.Is(err, ErrCheckpointNotFound) {
+		return nil, err
+	}
+
+	// Memory variables are loaded again on every call, so only the inputs given
+	// by the caller are part of the checkpoint.
+	checkpoint.RunID = runID
+	checkpoint.Inputs = make(map[string]string, len(inputs))
+	for key, value := range inputs {
+		checkpoint.Inputs[key] = value
+	}
+	if e.Memory != nil {
+		for _, key := range e.Memory.MemoryVariables(ctx) {
+			delete(checkpoint.Inputs, key)
+		}
+	}
+
+	return checkpoint, nil
+}
+
+func (e *Executor) saveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if e.CheckpointStore == nil || checkpoint.RunID == "" {
+		return nil
+	}
+
+	checkpoint.UpdatedAt = time.Now()
+	return e.CheckpointStore.Save(ctx, *checkpoint)
+}
+
+func (e *Executor) deleteCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if e.CheckpointStore == nil || checkpoint.RunID == "" {
+		return nil
+	}
+
+	return e.CheckpointStore.Delete(ctx, checkpoint.RunID)
 }
 
 func (e *Executor) doIteration( // nolint
@@ -79,6 +222,7 @@ func (e *Executor) doIteration( // nolint
 	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	inputs map[string]string,
+	budget *runBudget,
 ) ([]schema.AgentStep, map[string]any, error) {
 	actions, finish, err := e.Agent.Plan(ctx, steps, inputs)
 	if errors.Is(err, ErrUnableToParseOutput) && e.ErrorHandler != nil {
@@ -107,7 +251,7 @@ func (e *Executor) doIteration( // nolint
 	}
 
 	for _, action := range actions {
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
+		steps, err = e.doAction(ctx, steps, nameToTool, action, budget)
 		if err != nil {
 			return steps, nil, err
 		}
@@ -121,23 +265,33 @@ func (e *Executor) doAction(
 	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	action schema.AgentAction,
+	budget *runBudget,
 ) ([]schema.AgentStep, error) {
 	if e.CallbacksHandler != nil {
 		e.CallbacksHandler.HandleAgentAction(ctx, action)
 	}
+	emitEvent(ctx, Event{Type: EventAction, Action: &action})
 
 	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
 	if !ok {
+		observation := fmt.Sprintf("%s is not a valid tool, try another one", action.Tool)
+		emitEvent(ctx, Event{Type: EventInvalidTool, Action: &action, Observation: observation})
 		return append(steps, schema.AgentStep{
 			Action:      action,
-			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
+			Observation: observation,
 		}), nil
 	}
 
+	if err := budget.useToolCall(); err != nil {
+		return steps, err
+	}
+
+	emitEvent(ctx, Event{Type: EventToolStart, Action: &action})
 	observation, err := tool.Call(ctx, action.ToolInput)
 	if err != nil {
 		return nil, err
 	}
+	emitEvent(ctx, Event{Type: EventToolEnd, Action: &action, Observation: observation})
 
 	return append(steps, schema.AgentStep{
 		Action:      action,
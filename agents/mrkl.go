@@ -49,7 +49,7 @@ func NewOneShotAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *OneSho
 
 	return &OneShotZeroAgent{
 		Chain: chains.NewLLMChain(
-			llm,
+			usageTrackingModel{llm},
 			options.getMrklPrompt(tools),
 			chains.WithCallback(options.callbacksHandler),
 		),
@@ -73,14 +73,7 @@ func (a *OneShotZeroAgent) Plan(
 	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
 	fullInputs["today"] = time.Now().Format("January 02, 2006")
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, a.CallbacksHandler)
 
 	output, err := chains.Predict(
 		ctx,
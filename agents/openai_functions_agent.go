@@ -78,14 +78,7 @@ func (o *OpenAIFunctionsAgent) Plan(
 	}
 	fullInputs[agentScratchpad] = o.constructScratchPad(intermediateSteps)
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if o.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			o.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, o.CallbacksHandler)
 
 	prompt, err := o.Prompt.FormatPrompt(fullInputs)
 	if err != nil {
@@ -131,6 +124,7 @@ func (o *OpenAIFunctionsAgent) Plan(
 
 	result, err := o.LLM.GenerateContent(ctx, mcList,
 		llms.WithFunctions(o.functions()), llms.WithStreamingFunc(stream))
+	recordTokenUsage(ctx, result)
 	if err != nil {
 		return nil, nil, err
 	}
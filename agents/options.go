@@ -1,7 +1,10 @@
 package agents
 
 import (
+	"time"
+
 	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
@@ -13,6 +16,11 @@ type Options struct {
 	memory                  schema.Memory
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
+	checkpointStore         CheckpointStore
+	maxDelegationDepth      int
+	budget                  Budget
+	finalAnswerLLM          llms.Model
+	finalAnswerPrompt       prompts.PromptTemplate
 	maxIterations           int
 	returnIntermediateSteps bool
 	outputKey               string
@@ -62,6 +70,12 @@ func openAIFunctionsDefaultOptions() Options {
 	}
 }
 
+func chainToolDefaultOptions() Options {
+	return Options{
+		maxDelegationDepth: _defaultMaxDelegationDepth,
+	}
+}
+
 func (co Options) getMrklPrompt(tools []tools.Tool) prompts.PromptTemplate {
 	if co.prompt.Template != "" {
 		return co.prompt
@@ -161,6 +175,62 @@ func WithParserErrorHandler(errorHandler *ParserErrorHandler) Option {
 	}
 }
 
+// WithCheckpointStore is an option for setting the store an executor checkpoints its
+// runs to. Only runs with a run ID in their context are checkpointed, see ContextWithRunID.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(co *Options) {
+		co.checkpointStore = store
+	}
+}
+
+// WithMaxDelegationDepth is an option for setting how deeply calls delegated through a
+// chain tool may be nested. Zero means no limit.
+func WithMaxDelegationDepth(depth int) Option {
+	return func(co *Options) {
+		co.maxDelegationDepth = depth
+	}
+}
+
+// WithTokenBudget is an option for setting the max number of tokens the llm calls of
+// an executor run may use.
+func WithTokenBudget(maxTokens int) Option {
+	return func(co *Options) {
+		co.budget.MaxTokens = maxTokens
+	}
+}
+
+// WithTimeBudget is an option for setting the max wall-clock time of an executor run.
+func WithTimeBudget(maxDuration time.Duration) Option {
+	return func(co *Options) {
+		co.budget.MaxDuration = maxDuration
+	}
+}
+
+// WithToolCallBudget is an option for setting the max number of tool calls of an
+// executor run.
+func WithToolCallBudget(maxToolCalls int) Option {
+	return func(co *Options) {
+		co.budget.MaxToolCalls = maxToolCalls
+	}
+}
+
+// WithFinalAnswerLLM is an option for making the executor generate a final answer from
+// the steps taken with the given llm when a run exhausts its budget or max iterations,
+// instead of returning an error.
+func WithFinalAnswerLLM(llm llms.Model) Option {
+	return func(co *Options) {
+		co.finalAnswerLLM = llm
+	}
+}
+
+// WithFinalAnswerPrompt is an option for setting the prompt used to generate a final
+// answer with the final answer llm.
+func WithFinalAnswerPrompt(prompt prompts.PromptTemplate) Option {
+	return func(co *Options) {
+		co.finalAnswerPrompt = prompt
+	}
+}
+
 type OpenAIOption struct{}
 
 func NewOpenAIOption() OpenAIOption {
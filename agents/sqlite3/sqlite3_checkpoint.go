@@ -0,0 +1,99 @@
+// Package sqlite3 adds support for
+// persisting agent executor checkpoints using sqlite3.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/internal/sqlitedb"
+)
+
+// CheckpointStore is an agents.CheckpointStore that stores checkpoints in a sqlite3 table.
+type CheckpointStore struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the checkpoints table.
+	TableName string
+}
+
+// Statically assert that CheckpointStore implement the checkpoint store interface.
+var _ agents.CheckpointStore = &CheckpointStore{}
+
+// New creates a new CheckpointStore and creates its table if it does not exist.
+func New(ctx context.Context, options ...Option) (*CheckpointStore, error) {
+	s := applyOptions(options...)
+
+	if s.DB == nil {
+		db, err := sqlitedb.Open(s.DBAddress)
+		if err != nil {
+			return nil, err
+		}
+		s.DB = db
+	}
+
+	if _, err := s.DB.ExecContext(ctx, fmt.Sprintf(DefaultSchema, s.TableName)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save creates or replaces the checkpoint for the run ID of the checkpoint.
+func (s *CheckpointStore) Save(ctx context.Context, checkpoint agents.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	querytpl := []string{
+		"INSERT INTO ",
+		" (run_id, data, updated) VALUES (?, ?, ?)" +
+			" ON CONFLICT(run_id) DO UPDATE SET data = excluded.data, updated = excluded.updated;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err = s.DB.ExecContext(ctx, query, checkpoint.RunID, string(data), checkpoint.UpdatedAt)
+	return err
+}
+
+// Load returns the checkpoint for a run ID.
+func (s *CheckpointStore) Load(ctx context.Context, runID string) (*agents.Checkpoint, error) {
+	querytpl := []string{
+		"SELECT data FROM ",
+		" WHERE run_id = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+
+	var data string
+	err := s.DB.QueryRowContext(ctx, query, runID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", agents.ErrCheckpointNotFound, runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint agents.Checkpoint
+	if err := json.Unmarshal([]byte(data), &checkpoint); err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Delete removes the checkpoint for a run ID.
+func (s *CheckpointStore) Delete(ctx context.Context, runID string) error {
+	querytpl := []string{
+		"DELETE FROM ",
+		" WHERE run_id = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, runID)
+	return err
+}
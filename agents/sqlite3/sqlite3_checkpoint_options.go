@@ -0,0 +1,60 @@
+package sqlite3
+
+import (
+	"database/sql"
+)
+
+// DefaultTableName sets a default table name.
+const DefaultTableName = "langchaingo_agent_checkpoints"
+
+// DefaultSchema sets a default schema to be run after connecting.
+const DefaultSchema = `CREATE TABLE IF NOT EXISTS %s (
+		run_id TEXT PRIMARY KEY,
+		data TEXT NOT NULL,
+		updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);`
+
+// Option is a function for creating a new checkpoint store
+// with other than the default values.
+type Option func(s *CheckpointStore)
+
+// WithDB is an option for New for adding a database connection.
+func WithDB(db *sql.DB) Option {
+	return func(s *CheckpointStore) {
+		s.DB = db
+	}
+}
+
+// WithDBAddress is an option for New for specifying an address
+// or file path for when connecting the db.
+func WithDBAddress(addr string) Option {
+	return func(s *CheckpointStore) {
+		s.DBAddress = addr
+	}
+}
+
+// WithTableName is an option for New for setting the name of
+// the checkpoints table.
+func WithTableName(name string) Option {
+	return func(s *CheckpointStore) {
+		s.TableName = name
+	}
+}
+
+func applyOptions(options ...Option) *CheckpointStore {
+	s := &CheckpointStore{}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.TableName == "" {
+		s.TableName = DefaultTableName
+	}
+
+	if s.DBAddress == "" {
+		s.DBAddress = ":memory:"
+	}
+
+	return s
+}
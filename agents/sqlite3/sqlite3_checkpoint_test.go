@@ -0,0 +1,48 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/agents/sqlite3"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := sqlite3.New(ctx)
+	require.NoError(t, err)
+
+	_, err = s.Load(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	checkpoint := agents.Checkpoint{
+		RunID:  "run",
+		Inputs: map[string]string{"input": "foo"},
+		Steps: []schema.AgentStep{{
+			Action:      schema.AgentAction{Tool: "calculator", ToolInput: "1+1", ToolID: "call_1"},
+			Observation: "2",
+		}},
+		Iterations: 1,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	require.NoError(t, s.Save(ctx, checkpoint))
+
+	checkpoint.Iterations = 2
+	require.NoError(t, s.Save(ctx, checkpoint))
+
+	loaded, err := s.Load(ctx, "run")
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.Steps, loaded.Steps)
+	require.Equal(t, checkpoint.Inputs, loaded.Inputs)
+	require.Equal(t, 2, loaded.Iterations)
+
+	require.NoError(t, s.Delete(ctx, "run"))
+	_, err = s.Load(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
@@ -0,0 +1,191 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	_defaultMaxDelegationDepth = 3
+
+	_defaultSupervisorPrefix = `Today is {{.today}}.
+You are a supervisor coordinating a team of workers. Break the question into sub-tasks and
+delegate each sub-task to the worker best suited for it. Give every worker a complete,
+self-contained instruction. Combine the results of the workers into the final answer.
+You have access to the following workers:
+
+{{.tool_descriptions}}`
+)
+
+type delegationPathKey struct{}
+
+// DelegationPath returns the names of the workers a call is nested in, outermost
+// first. It is empty outside of a call delegated through a ChainTool. Callbacks
+// handlers can use it to tell which agent of a supervisor an event belongs to.
+func DelegationPath(ctx context.Context) []string {
+	path, _ := ctx.Value(delegationPathKey{}).([]string)
+	return path
+}
+
+// ChainTool is a tool that delegates its input to a chain. Since an Executor is a
+// chain, it can be used to expose a whole agent as a worker of another agent.
+type ChainTool struct {
+	// Chain is the chain the tool input is delegated to.
+	Chain chains.Chain
+	// Memory is an optional memory shared with the chain. Its variables are loaded
+	// and passed to the chain as inputs, but the delegated call is not saved to it.
+	Memory schema.Memory
+	// CallbacksHandler is the handler for tool callbacks.
+	CallbacksHandler callbacks.Handler
+	// InputKey is the input key of the chain the tool input is given as. If empty,
+	// the chain must have exactly one input key not provided by a memory.
+	InputKey string
+	// OutputKey is the output key of the chain returned as the tool output. If empty,
+	// the chain must have exactly one output key.
+	OutputKey string
+	// MaxDepth is the max number of nested delegations a call can be part of.
+	MaxDepth int
+
+	name        string
+	description string
+}
+
+var _ tools.Tool = &ChainTool{}
+
+// NewChainTool creates a new tool with the given name and description delegating to
+// a chain. The memory, callbacks handler and output key options of the agents package
+// apply to the tool, as well as WithMaxDelegationDepth.
+func NewChainTool(name, description string, chain chains.Chain, opts ...Option) *ChainTool {
+	options := chainToolDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ChainTool{
+		Chain:            chain,
+		Memory:           options.memory,
+		CallbacksHandler: options.callbacksHandler,
+		OutputKey:        options.outputKey,
+		MaxDepth:         options.maxDelegationDepth,
+		name:             name,
+		description:      description,
+	}
+}
+
+// Name returns the name of the tool.
+func (t *ChainTool) Name() string {
+	return t.name
+}
+
+// Description returns the description of the tool.
+func (t *ChainTool) Description() string {
+	return t.description
+}
+
+// Call delegates the input to the chain and returns its output. The name of the
+// tool is appended to the delegation path of the context the chain is called with.
+func (t *ChainTool) Call(ctx context.Context, input string) (string, error) {
+	path := DelegationPath(ctx)
+	if t.MaxDepth > 0 && len(path) >= t.MaxDepth {
+		return "", fmt.Errorf("%w: %s", ErrMaxDelegationDepth, strings.Join(append(path, t.name), " > "))
+	}
+	ctx = context.WithValue(ctx, delegationPathKey{}, append(path[:len(path):len(path)], t.name))
+	// The run ID of the delegating executor must not be checkpointed by the worker.
+	ctx = ContextWithRunID(ctx, "")
+
+	if t.CallbacksHandler != nil {
+		t.CallbacksHandler.HandleToolStart(ctx, input)
+	}
+
+	output, err := t.call(ctx, input)
+	if err != nil {
+		if t.CallbacksHandler != nil {
+			t.CallbacksHandler.HandleToolError(ctx, err)
+		}
+		return "", err
+	}
+
+	if t.CallbacksHandler != nil {
+		t.CallbacksHandler.HandleToolEnd(ctx, output)
+	}
+
+	return output, nil
+}
+
+func (t *ChainTool) call(ctx context.Context, input string) (string, error) {
+	inputValues := make(map[string]any)
+	if t.Memory != nil {
+		memoryValues, err := t.Memory.LoadMemoryVariables(ctx, map[string]any{})
+		if err != nil {
+			return "", err
+		}
+		for key, value := range memoryValues {
+			inputValues[key] = value
+		}
+	}
+
+	inputKey, err := t.inputKey(ctx, inputValues)
+	if err != nil {
+		return "", err
+	}
+	inputValues[inputKey] = input
+
+	outputValues, err := chains.Call(ctx, t.Chain, inputValues)
+	if err != nil {
+		return "", err
+	}
+
+	outputKey := t.OutputKey
+	if outputKey == "" {
+		outputKeys := t.Chain.GetOutputKeys()
+		if len(outputKeys) != 1 {
+			return "", chains.ErrMultipleOutputsInRun
+		}
+		outputKey = outputKeys[0]
+	}
+
+	output, ok := outputValues[outputKey].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", chains.ErrWrongOutputTypeInRun, outputKey)
+	}
+
+	return output, nil
+}
+
+func (t *ChainTool) inputKey(ctx context.Context, provided map[string]any) (string, error) {
+	if t.InputKey != "" {
+		return t.InputKey, nil
+	}
+
+	chainMemoryKeys := t.Chain.GetMemory().MemoryVariables(ctx)
+	neededKeys := make([]string, 0)
+	for _, key := range t.Chain.GetInputKeys() {
+		if _, ok := provided[key]; ok || slices.Contains(chainMemoryKeys, key) {
+			continue
+		}
+		neededKeys = append(neededKeys, key)
+	}
+	if len(neededKeys) != 1 {
+		return "", chains.ErrMultipleInputsInRun
+	}
+
+	return neededKeys[0], nil
+}
+
+// NewSupervisor creates an executor running an agent that breaks its input into
+// sub-tasks and delegates them to the given workers, usually created with
+// NewChainTool. The options are applied to both the agent and the executor, so
+// WithMemory sets the memory of the supervisor, which can be shared with the
+// workers by passing the same memory to NewChainTool.
+func NewSupervisor(llm llms.Model, workers []tools.Tool, opts ...Option) *Executor {
+	opts = append([]Option{WithPromptPrefix(_defaultSupervisorPrefix)}, opts...)
+	return NewExecutor(NewOneShotAgent(llm, workers, opts...), opts...)
+}
@@ -0,0 +1,57 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestSupervisor(t *testing.T) {
+	t.Parallel()
+
+	worker := agents.NewExecutor(agents.NewOneShotAgent(
+		&scriptedLLM{responses: []string{
+			"Action: calculator\nAction Input: 6*7",
+			"Final Answer: 42",
+		}},
+		[]tools.Tool{tools.Calculator{}},
+	))
+	supervisor := agents.NewSupervisor(
+		&scriptedLLM{responses: []string{
+			"Action: math\nAction Input: multiply 6 by 7",
+			"Final Answer: the result is 42",
+		}},
+		[]tools.Tool{agents.NewChainTool("math", "Worker that can do math.", worker)},
+		agents.WithReturnIntermediateSteps(),
+	)
+
+	var workerEvents int
+	for event := range supervisor.Stream(context.Background(), map[string]any{"input": "what is 6*7?"}) {
+		if event.Agent == "math" {
+			workerEvents++
+		}
+		if event.Type == agents.EventFinish {
+			require.Equal(t, " the result is 42", event.Outputs["output"])
+		}
+		require.NotEqual(t, agents.EventError, event.Type, event.Err)
+	}
+	require.Positive(t, workerEvents)
+}
+
+func TestChainToolMaxDelegationDepth(t *testing.T) {
+	t.Parallel()
+
+	self := agents.NewChainTool("self", "Delegates to itself.", nil, agents.WithMaxDelegationDepth(2))
+	self.Chain = agents.NewExecutor(agents.NewOneShotAgent(
+		&scriptedLLM{responses: []string{"Action: self\nAction Input: again"}},
+		[]tools.Tool{self},
+	))
+
+	_, err := chains.Run(context.Background(), self.Chain, "start")
+	require.ErrorIs(t, err, agents.ErrMaxDelegationDepth)
+	require.ErrorContains(t, err, "self > self > self")
+}
@@ -0,0 +1,95 @@
+// Package docstore provides key-value stores for documents, such as the parent
+// documents of the chunks indexed in a vector store.
+package docstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrMismatchedKeys is returned by Set when the numbers of keys and documents do
+// not match.
+var ErrMismatchedKeys = errors.New("number of keys and documents does not match")
+
+// Docstore is the interface for storing documents by key.
+type Docstore interface {
+	// Get returns the documents of the keys that exist, in the order of the keys.
+	Get(ctx context.Context, keys []string) ([]schema.Document, error)
+	// Set creates or replaces the documents of the keys.
+	Set(ctx context.Context, keys []string, docs []schema.Document) error
+	// Delete removes the documents of the keys.
+	Delete(ctx context.Context, keys []string) error
+}
+
+// InMemory is a Docstore that keeps documents in memory. It is safe for
+// concurrent use.
+type InMemory struct {
+	mu   sync.RWMutex
+	docs map[string]schema.Document
+}
+
+var _ Docstore = &InMemory{}
+
+// NewInMemory creates a new in-memory docstore.
+func NewInMemory() *InMemory {
+	return &InMemory{
+		docs: make(map[string]schema.Document),
+	}
+}
+
+// Get returns the documents of the keys that exist, in the order of the keys.
+func (s *InMemory) Get(_ context.Context, keys []string) ([]schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	docs := make([]schema.Document, 0, len(keys))
+	for _, key := range keys {
+		if doc, ok := s.docs[key]; ok {
+			docs = append(docs, copyDocument(doc))
+		}
+	}
+	return docs, nil
+}
+
+// Set creates or replaces the documents of the keys.
+func (s *InMemory) Set(_ context.Context, keys []string, docs []schema.Document) error {
+	if len(keys) != len(docs) {
+		return ErrMismatchedKeys
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, key := range keys {
+		s.docs[key] = copyDocument(docs[i])
+	}
+	return nil
+}
+
+// Delete removes the documents of the keys.
+func (s *InMemory) Delete(_ context.Context, keys []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.docs, key)
+	}
+	return nil
+}
+
+// copyDocument copies the metadata of a document, so changes to the metadata of
+// documents returned do not change the stored documents.
+func copyDocument(doc schema.Document) schema.Document {
+	if doc.Metadata == nil {
+		return doc
+	}
+	metadata := make(map[string]any, len(doc.Metadata))
+	for key, value := range doc.Metadata {
+		metadata[key] = value
+	}
+	doc.Metadata = metadata
+	return doc
+}
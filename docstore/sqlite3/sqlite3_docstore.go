@@ -0,0 +1,151 @@
+// Package sqlite3 adds support for
+// storing documents using sqlite3.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/docstore"
+	"github.com/tmc/langchaingo/internal/sqlitedb"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// maxVariables is the number of keys put in one query, below the limit of
+// variables of sqlite.
+const maxVariables = 500
+
+// Docstore is a docstore.Docstore that stores documents in a sqlite3 table.
+// The metadata of documents is stored as JSON.
+type Docstore struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the documents table.
+	TableName string
+}
+
+// Statically assert that Docstore implement the docstore interface.
+var _ docstore.Docstore = &Docstore{}
+
+// New creates a new Docstore and creates its table if it does not exist.
+func New(ctx context.Context, options ...Option) (*Docstore, error) {
+	s := applyOptions(options...)
+
+	if s.DB == nil {
+		db, err := sqlitedb.Open(s.DBAddress)
+		if err != nil {
+			return nil, err
+		}
+		s.DB = db
+	}
+
+	if _, err := s.DB.ExecContext(ctx, fmt.Sprintf(DefaultSchema, s.TableName)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get returns the documents of the keys that exist, in the order of the keys.
+func (s *Docstore) Get(ctx context.Context, keys []string) ([]schema.Document, error) {
+	found := make(map[string]schema.Document, len(keys))
+	for start := 0; start < len(keys); start += maxVariables {
+		batch := keys[start:min(start+maxVariables, len(keys))]
+		query := fmt.Sprintf("SELECT key, content, metadata FROM %s WHERE key IN (%s);",
+			s.TableName, strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", "))
+		if err := s.query(ctx, query, toArgs(batch), found); err != nil {
+			return nil, err
+		}
+	}
+
+	docs := make([]schema.Document, 0, len(found))
+	for _, key := range keys {
+		if doc, ok := found[key]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, nil
+}
+
+// Set creates or replaces the documents of the keys.
+func (s *Docstore) Set(ctx context.Context, keys []string, docs []schema.Document) error {
+	if len(keys) != len(docs) {
+		return docstore.ErrMismatchedKeys
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	querytpl := []string{
+		"INSERT INTO ",
+		" (key, content, metadata) VALUES (?, ?, ?)" +
+			" ON CONFLICT(key) DO UPDATE SET content = excluded.content, metadata = excluded.metadata;",
+	}
+	stmt, err := tx.PrepareContext(ctx, strings.Join(querytpl, s.TableName))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, doc := range docs {
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, keys[i], doc.PageContent, string(metadata)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the documents of the keys.
+func (s *Docstore) Delete(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += maxVariables {
+		batch := keys[start:min(start+maxVariables, len(keys))]
+		query := fmt.Sprintf("DELETE FROM %s WHERE key IN (%s);",
+			s.TableName, strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", "))
+		if _, err := s.DB.ExecContext(ctx, query, toArgs(batch)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Docstore) query(ctx context.Context, query string, args []any, found map[string]schema.Document) error {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, content, metadata string
+		if err := rows.Scan(&key, &content, &metadata); err != nil {
+			return err
+		}
+		doc := schema.Document{PageContent: content}
+		if err := json.Unmarshal([]byte(metadata), &doc.Metadata); err != nil {
+			return err
+		}
+		found[key] = doc
+	}
+	return rows.Err()
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
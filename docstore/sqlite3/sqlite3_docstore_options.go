@@ -0,0 +1,60 @@
+package sqlite3
+
+import (
+	"database/sql"
+)
+
+// DefaultTableName sets a default table name.
+const DefaultTableName = "langchaingo_documents"
+
+// DefaultSchema sets a default schema to be run after connecting.
+const DefaultSchema = `CREATE TABLE IF NOT EXISTS %s (
+		key TEXT PRIMARY KEY,
+		content TEXT NOT NULL,
+		metadata TEXT NOT NULL
+);`
+
+// Option is a function for creating a new docstore
+// with other than the default values.
+type Option func(s *Docstore)
+
+// WithDB is an option for New for adding a database connection.
+func WithDB(db *sql.DB) Option {
+	return func(s *Docstore) {
+		s.DB = db
+	}
+}
+
+// WithDBAddress is an option for New for specifying an address
+// or file path for when connecting the db.
+func WithDBAddress(addr string) Option {
+	return func(s *Docstore) {
+		s.DBAddress = addr
+	}
+}
+
+// WithTableName is an option for New for setting the name of
+// the documents table.
+func WithTableName(name string) Option {
+	return func(s *Docstore) {
+		s.TableName = name
+	}
+}
+
+func applyOptions(options ...Option) *Docstore {
+	s := &Docstore{}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.TableName == "" {
+		s.TableName = DefaultTableName
+	}
+
+	if s.DBAddress == "" {
+		s.DBAddress = ":memory:"
+	}
+
+	return s
+}
@@ -0,0 +1,36 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/docstore"
+	"github.com/tmc/langchaingo/docstore/sqlite3"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestDocstore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := sqlite3.New(ctx)
+	require.NoError(t, err)
+
+	docs := []schema.Document{
+		{PageContent: "foo", Metadata: map[string]any{"source": "a.txt", "page": float64(1)}},
+		{PageContent: "bar", Metadata: map[string]any{}},
+	}
+	require.NoError(t, s.Set(ctx, []string{"a", "b"}, docs))
+	require.ErrorIs(t, s.Set(ctx, []string{"a"}, docs), docstore.ErrMismatchedKeys)
+
+	got, err := s.Get(ctx, []string{"b", "c", "a"})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{docs[1], docs[0]}, got)
+
+	require.NoError(t, s.Set(ctx, []string{"a"}, []schema.Document{{PageContent: "baz", Metadata: map[string]any{}}}))
+	require.NoError(t, s.Delete(ctx, []string{"b"}))
+	got, err = s.Get(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{{PageContent: "baz", Metadata: map[string]any{}}}, got)
+}
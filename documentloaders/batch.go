@@ -0,0 +1,73 @@
+package documentloaders
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// ErrInvalidBatchSize is returned by SplitInBatches for batch sizes less than one.
+var ErrInvalidBatchSize = errors.New("batch size must be at least one")
+
+// SplitInBatches loads the documents of a lazy loader one at a time, splits them
+// with the text splitter and calls fn with the chunks in batches of at most
+// batchSize, so only one batch and the chunks of one document are held in memory.
+// If the splitter is nil the documents are batched as they are. It can be used to
+// fill a vector store from a large source:
+//
+//	err := documentloaders.SplitInBatches(ctx, loader, splitter, 100,
+//		func(ctx context.Context, docs []schema.Document) error {
+//			_, err := store.AddDocuments(ctx, docs)
+//			return err
+//		})
+func SplitInBatches(
+	ctx context.Context,
+	loader LazyLoader,
+	splitter textsplitter.TextSplitter,
+	batchSize int,
+	fn func(ctx context.Context, docs []schema.Document) error,
+) error {
+	if batchSize < 1 {
+		return ErrInvalidBatchSize
+	}
+
+	batch := make([]schema.Document, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(ctx, batch); err != nil {
+			return err
+		}
+		batch = make([]schema.Document, 0, batchSize)
+		return nil
+	}
+
+	err := loader.LoadLazy(ctx, func(doc schema.Document) error {
+		chunks := []schema.Document{doc}
+		if splitter != nil {
+			var err error
+			chunks, err = textsplitter.SplitDocuments(splitter, chunks)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, chunk := range chunks {
+			batch = append(batch, chunk)
+			if len(batch) == batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
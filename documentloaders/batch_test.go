@@ -0,0 +1,83 @@
+package documentloaders
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+func TestSplitInBatches(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/test.csv")
+	require.NoError(t, err)
+	defer file.Close()
+
+	var sizes []int
+	var rows []any
+	err = SplitInBatches(context.Background(), NewCSV(file, "name"), nil, 6,
+		func(_ context.Context, docs []schema.Document) error {
+			sizes = append(sizes, len(docs))
+			for _, doc := range docs {
+				rows = append(rows, doc.Metadata["row"])
+			}
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []int{6, 6, 6, 2}, sizes)
+	assert.Len(t, rows, 20)
+	assert.Equal(t, 20, rows[19])
+}
+
+func TestSplitInBatchesSplitter(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/test.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	splitter := textsplitter.NewRecursiveCharacter(
+		textsplitter.WithChunkSize(3),
+		textsplitter.WithChunkOverlap(0),
+	)
+
+	var batches [][]string
+	err = SplitInBatches(context.Background(), NewText(file), splitter, 2,
+		func(_ context.Context, docs []schema.Document) error {
+			var batch []string
+			for _, doc := range docs {
+				batch = append(batch, doc.PageContent)
+			}
+			batches = append(batches, batch)
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Foo", "Bar"}, {"Baz"}}, batches)
+}
+
+func TestSplitInBatchesError(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/test.csv")
+	require.NoError(t, err)
+	defer file.Close()
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = SplitInBatches(context.Background(), NewCSV(file), nil, 5,
+		func(_ context.Context, _ []schema.Document) error {
+			calls++
+			return errStop
+		})
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+
+	err = SplitInBatches(context.Background(), NewCSV(file), nil, 0, nil)
+	require.ErrorIs(t, err, ErrInvalidBatchSize)
+}
@@ -19,7 +19,7 @@ type CSV struct {
 	columns []string
 }
 
-var _ Loader = CSV{}
+var _ LazyLoader = CSV{}
 
 // NewCSV creates a new csv loader with an io.Reader and optional column names for filtering.
 func NewCSV(r io.Reader, columns ...string) CSV {
@@ -29,20 +29,29 @@ func NewCSV(r io.Reader, columns ...string) CSV {
 	}
 }
 
-// Load reads from the io.Reader and returns a single document with the data.
-func (c CSV) Load(_ context.Context) ([]schema.Document, error) {
+// Load reads from the io.Reader and returns a document for every row.
+func (c CSV) Load(ctx context.Context) ([]schema.Document, error) {
+	return collect(ctx, c.LoadLazy)
+}
+
+// LoadLazy reads from the io.Reader and calls yield with a document for every row,
+// reading only one row at a time.
+func (c CSV) LoadLazy(ctx context.Context, yield func(schema.Document) error) error {
 	var header []string
-	var docs []schema.Document
 	var rown int
 
 	rd := csv.NewReader(c.r)
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		row, err := rd.Read()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if len(header) == 0 {
 			header = append(header, row...)
@@ -62,13 +71,16 @@ func (c CSV) Load(_ context.Context) ([]schema.Document, error) {
 		}
 
 		rown++
-		docs = append(docs, schema.Document{
+		err = yield(schema.Document{
 			PageContent: strings.Join(content, "\n"),
 			Metadata:    map[string]any{"row": rown},
 		})
+		if err != nil {
+			return err
+		}
 	}
 
-	return docs, nil
+	return nil
 }
 
 // LoadAndSplit reads text data from the io.Reader and splits it into multiple
@@ -0,0 +1,457 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const (
+	_defaultDirectoryConcurrency = 4
+	_sniffLen                    = 512
+)
+
+// ErrNoFileLoader is returned by FileLoaderFor if no loader is registered for a file.
+var ErrNoFileLoader = errors.New("no loader for file")
+
+// FileLoaderFunc creates the loader for a file of a directory. The file is closed
+// after the documents of the loader have been loaded.
+type FileLoaderFunc func(f fs.File, info fs.FileInfo) (Loader, error)
+
+// Directory loads the files of a directory tree, using a loader chosen by the
+// extension or the sniffed MIME type of each file. Files without a loader are
+// skipped. The documents of each file carry the metadata of its loader along with
+// the "source", "path" and "mod_time" of the file.
+type Directory struct {
+	fsys        fs.FS
+	dir         string
+	include     []string
+	exclude     []string
+	extLoaders  map[string]FileLoaderFunc
+	mimeLoaders map[string]FileLoaderFunc
+	sniff       bool
+	concurrency int
+}
+
+var _ LazyLoader = Directory{}
+
+// DirectoryOption is a function for configuring a directory loader.
+type DirectoryOption func(d *Directory)
+
+// WithInclude sets glob patterns a file must match to be loaded. Patterns without a
+// "/" are matched against the name of the file, others against its path relative to
+// the directory, where "**" matches any number of path segments.
+func WithInclude(patterns ...string) DirectoryOption {
+	return func(d *Directory) {
+		d.include = append(d.include, patterns...)
+	}
+}
+
+// WithExclude sets glob patterns of files and directories not to load. Patterns are
+// matched as in WithInclude.
+func WithExclude(patterns ...string) DirectoryOption {
+	return func(d *Directory) {
+		d.exclude = append(d.exclude, patterns...)
+	}
+}
+
+// WithExtensionLoader sets the loader used for files with the extension, such as ".txt".
+func WithExtensionLoader(ext string, loader FileLoaderFunc) DirectoryOption {
+	return func(d *Directory) {
+		d.extLoaders[strings.ToLower(ext)] = loader
+	}
+}
+
+// WithMIMELoader sets the loader used for files with an unknown extension whose
+// content is sniffed as the MIME type, such as "text/plain".
+func WithMIMELoader(mimeType string, loader FileLoaderFunc) DirectoryOption {
+	return func(d *Directory) {
+		d.mimeLoaders[mimeType] = loader
+	}
+}
+
+// WithMIMESniffing sets whether the MIME type of files with an unknown extension is
+// sniffed from their content to choose a loader. It is enabled by default.
+func WithMIMESniffing(sniff bool) DirectoryOption {
+	return func(d *Directory) {
+		d.sniff = sniff
+	}
+}
+
+// WithConcurrency sets the max number of files loaded concurrently.
+func WithConcurrency(n int) DirectoryOption {
+	return func(d *Directory) {
+		d.concurrency = n
+	}
+}
+
+// NewDirectory creates a new loader for the files of a directory on disk.
+func NewDirectory(dir string, opts ...DirectoryOption) Directory {
+	d := NewFS(os.DirFS(dir), opts...)
+	d.dir = dir
+	return d
+}
+
+// NewFS creates a new loader for the files of a file system, such as an embed.FS.
+func NewFS(fsys fs.FS, opts ...DirectoryOption) Directory {
+	d := Directory{
+		fsys: fsys,
+		extLoaders: map[string]FileLoaderFunc{
+			".txt":      textFileLoader,
+			".md":       markdownFileLoader,
+			".markdown": markdownFileLoader,
+			".html":     htmlFileLoader,
+			".htm":      htmlFileLoader,
+			".csv":      csvFileLoader,
+			".pdf":      pdfFileLoader,
+			".docx":     docxFileLoader,
+			".xlsx":     xlsxFileLoader,
+			".epub":     epubFileLoader,
+			".json":     jsonFileLoader,
+			".jsonl":    jsonLinesFileLoader,
+		},
+		mimeLoaders: map[string]FileLoaderFunc{
+			"text/plain":      textFileLoader,
+			"text/html":       htmlFileLoader,
+			"text/csv":        csvFileLoader,
+			"application/pdf": pdfFileLoader,
+		},
+		sniff:       true,
+		concurrency: _defaultDirectoryConcurrency,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if d.concurrency < 1 {
+		d.concurrency = 1
+	}
+	return d
+}
+
+// Load walks the directory and loads its files concurrently. The documents are
+// returned in the lexical order of the paths of the files.
+func (d Directory) Load(ctx context.Context) ([]schema.Document, error) {
+	paths, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		docs []schema.Document
+		err  error
+	}
+	results := make([]result, len(paths))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(d.concurrency)
+	for w := 0; w < d.concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				docs, err := d.loadFile(ctx, paths[i])
+				results[i] = result{docs: docs, err: err}
+			}
+		}()
+	}
+
+	for i := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(paths))
+	for _, r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+		docs = append(docs, r.docs...)
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit loads the files of the directory and splits the documents using a
+// text splitter.
+func (d Directory) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := d.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// files returns the paths of the files to load.
+func (d Directory) files() ([]string, error) {
+	var paths []string
+	err := fs.WalkDir(d.fsys, ".", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		if matchAny(d.exclude, p) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !entry.Type().IsRegular() {
+			return nil
+		}
+		if len(d.include) > 0 && !matchAny(d.include, p) {
+			return nil
+		}
+		paths = append(paths, p)
+		return nil
+	})
+
+	return paths, err
+}
+
+// LoadLazy walks the directory and calls yield with the documents of its files in
+// the lexical order of their paths. Files are loaded one at a time, and lazily if
+// their loader is a LazyLoader.
+func (d Directory) LoadLazy(ctx context.Context, yield func(schema.Document) error) error {
+	paths, err := d.files()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		if err := d.loadFileLazy(ctx, p, yield); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d Directory) loadFile(ctx context.Context, p string) ([]schema.Document, error) {
+	return collect(ctx, func(ctx context.Context, yield func(schema.Document) error) error {
+		return d.loadFileLazy(ctx, p, yield)
+	})
+}
+
+func (d Directory) loadFileLazy(ctx context.Context, p string, yield func(schema.Document) error) error {
+	loaderFunc, err := d.FileLoaderFor(p)
+	if errors.Is(err, ErrNoFileLoader) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := d.fsys.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	loader, err := loaderFunc(f, info)
+	if err != nil {
+		return err
+	}
+
+	withMetadata := func(doc schema.Document) error {
+		if doc.Metadata == nil {
+			doc.Metadata = make(map[string]any, 3)
+		}
+		doc.Metadata["source"] = d.source(p)
+		doc.Metadata["path"] = p
+		doc.Metadata["mod_time"] = info.ModTime()
+		return yield(doc)
+	}
+
+	if lazy, ok := loader.(LazyLoader); ok {
+		return lazy.LoadLazy(ctx, withMetadata)
+	}
+
+	docs, err := loader.Load(ctx)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if err := withMetadata(doc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FileLoaderFor returns the loader used for the file at path p of the directory.
+func (d Directory) FileLoaderFor(p string) (FileLoaderFunc, error) {
+	if loader, ok := d.extLoaders[strings.ToLower(path.Ext(p))]; ok {
+		return loader, nil
+	}
+	if !d.sniff {
+		return nil, ErrNoFileLoader
+	}
+
+	f, err := d.fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := make([]byte, _sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return nil, err
+	}
+	if loader, ok := d.mimeLoaders[mimeType]; ok {
+		return loader, nil
+	}
+
+	return nil, ErrNoFileLoader
+}
+
+func (d Directory) source(p string) string {
+	if d.dir == "" {
+		return p
+	}
+	return filepath.Join(d.dir, filepath.FromSlash(p))
+}
+
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the slash separated path p matches the pattern. A
+// pattern without a "/" is matched against the last element of p only.
+func matchGlob(pattern, p string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+func textFileLoader(f fs.File, _ fs.FileInfo) (Loader, error) { //nolint:ireturn
+	return NewText(f), nil
+}
+
+func htmlFileLoader(f fs.File, _ fs.FileInfo) (Loader, error) { //nolint:ireturn
+	return NewHTML(f), nil
+}
+
+func csvFileLoader(f fs.File, _ fs.FileInfo) (Loader, error) { //nolint:ireturn
+	return NewCSV(f), nil
+}
+
+func markdownFileLoader(f fs.File, _ fs.FileInfo) (Loader, error) { //nolint:ireturn
+	return NewMarkdown(f), nil
+}
+
+func jsonFileLoader(f fs.File, _ fs.FileInfo) (Loader, error) { //nolint:ireturn
+	return NewJSON(f), nil
+}
+
+func jsonLinesFileLoader(f fs.File, _ fs.FileInfo) (Loader, error) { //nolint:ireturn
+	return NewJSON(f, WithJSONLines()), nil
+}
+
+func pdfFileLoader(f fs.File, info fs.FileInfo) (Loader, error) { //nolint:ireturn
+	r, size, err := readerAt(f, info)
+	if err != nil {
+		return nil, err
+	}
+	return NewPDF(r, size), nil
+}
+
+func docxFileLoader(f fs.File, info fs.FileInfo) (Loader, error) { //nolint:ireturn
+	r, size, err := readerAt(f, info)
+	if err != nil {
+		return nil, err
+	}
+	return NewDOCX(r, size), nil
+}
+
+func xlsxFileLoader(f fs.File, info fs.FileInfo) (Loader, error) { //nolint:ireturn
+	r, size, err := readerAt(f, info)
+	if err != nil {
+		return nil, err
+	}
+	return NewXLSX(r, size), nil
+}
+
+func epubFileLoader(f fs.File, info fs.FileInfo) (Loader, error) { //nolint:ireturn
+	r, size, err := readerAt(f, info)
+	if err != nil {
+		return nil, err
+	}
+	return NewEPUB(r, size), nil
+}
+
+// readerAt returns the file as an io.ReaderAt, reading it into memory if it does not
+// support random access.
+func readerAt(f fs.File, info fs.FileInfo) (io.ReaderAt, int64, error) {
+	if r, ok := f.(io.ReaderAt); ok {
+		return r, info.Size(), nil
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
@@ -0,0 +1,137 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestDirectoryLoader(t *testing.T) {
+	t.Parallel()
+
+	pdf, err := os.ReadFile("./testdata/sample.pdf")
+	require.NoError(t, err)
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"a.txt":             {Data: []byte("Foo Bar Baz"), ModTime: modTime},
+		"docs/b.md":         {Data: []byte("# Title"), ModTime: modTime},
+		"docs/c.csv":        {Data: []byte("name,age\nfoo,1\nbar,2\n"), ModTime: modTime},
+		"docs/d.pdf":        {Data: pdf, ModTime: modTime},
+		"docs/README":       {Data: []byte("plain text without extension"), ModTime: modTime},
+		"docs/image.bin":    {Data: []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0}, ModTime: modTime},
+		"skip/e.txt":        {Data: []byte("excluded"), ModTime: modTime},
+		"docs/nested/f.txt": {Data: []byte("nested"), ModTime: modTime},
+	}
+
+	t.Run("all files", func(t *testing.T) {
+		t.Parallel()
+
+		docs, err := NewFS(fsys, WithExclude("skip")).Load(context.Background())
+		require.NoError(t, err)
+
+		var paths []string
+		for _, doc := range docs {
+			paths = append(paths, doc.Metadata["path"].(string)) //nolint:forcetypeassert
+			assert.Equal(t, modTime, doc.Metadata["mod_time"])
+		}
+		assert.Equal(t, []string{
+			"a.txt",
+			"docs/README",
+			"docs/b.md",
+			"docs/c.csv",
+			"docs/c.csv",
+			"docs/d.pdf",
+			"docs/d.pdf",
+			"docs/nested/f.txt",
+		}, paths)
+
+		assert.Equal(t, "plain text without extension", docs[1].PageContent)
+		assert.Equal(t, "name: bar\nage: 2", docs[4].PageContent)
+		assert.Equal(t, 2, docs[4].Metadata["row"])
+		assert.Equal(t, 2, docs[6].Metadata["page"])
+	})
+
+	t.Run("include globs", func(t *testing.T) {
+		t.Parallel()
+
+		docs, err := NewFS(fsys, WithInclude("docs/**/*.txt", "*.md"), WithConcurrency(1)).
+			Load(context.Background())
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		assert.Equal(t, "# Title", docs[0].PageContent)
+		assert.Equal(t, "nested", docs[1].PageContent)
+	})
+
+	t.Run("custom loaders", func(t *testing.T) {
+		t.Parallel()
+
+		docs, err := NewFS(fsys,
+			WithInclude("*.bin", "README"),
+			WithMIMESniffing(false),
+			WithExtensionLoader(".BIN", textFileLoader),
+		).Load(context.Background())
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, "docs/image.bin", docs[0].Metadata["path"])
+	})
+}
+
+func TestDirectoryLoaderSource(t *testing.T) {
+	t.Parallel()
+
+	docs, err := NewDirectory("./testdata", WithInclude("*.txt")).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "Foo Bar Baz", docs[0].PageContent)
+	assert.Equal(t, filepath.Join("testdata", "test.txt"), docs[0].Metadata["source"])
+	assert.Equal(t, "test.txt", docs[0].Metadata["path"])
+}
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.txt", "a/b/c.txt", true},
+		{"*.txt", "a/b/c.md", false},
+		{"a/*.txt", "a/c.txt", true},
+		{"a/*.txt", "a/b/c.txt", false},
+		{"a/**/*.txt", "a/c.txt", true},
+		{"a/**/*.txt", "a/b/d/c.txt", true},
+		{"**/b", "a/b", true},
+		{"a/**", "a/b/c", true},
+		{"b/**", "a/b/c", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, matchGlob(c.pattern, c.path), "%s %s", c.pattern, c.path)
+	}
+}
+
+func TestDirectoryLoadLazy(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("a")},
+		"b.csv": {Data: []byte("name\nfoo\nbar\n")},
+		"c.txt": {Data: []byte("c")},
+	}
+
+	var contents []string
+	err := NewFS(fsys).LoadLazy(context.Background(), func(doc schema.Document) error {
+		contents = append(contents, doc.PageContent)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "name: foo", "name: bar", "c"}, contents)
+}
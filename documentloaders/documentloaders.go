@@ -14,3 +14,25 @@ type Loader interface {
 	// LoadAndSplit loads from a source and splits the documents using a text splitter.
 	LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error)
 }
+
+// LazyLoader is the interface for loaders that can load the documents of a source
+// one at a time, without holding all of them in memory.
+type LazyLoader interface {
+	Loader
+	// LoadLazy loads from a source and calls yield with every document in order. It
+	// stops at the first error, which is returned, including an error from yield.
+	LoadLazy(ctx context.Context, yield func(schema.Document) error) error
+}
+
+// collect loads all documents of a lazy loader.
+func collect(ctx context.Context, loadLazy func(context.Context, func(schema.Document) error) error) ([]schema.Document, error) { //nolint:lll
+	docs := []schema.Document{}
+	err := loadLazy(ctx, func(doc schema.Document) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
@@ -0,0 +1,169 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// DOCX loads the text of the paragraphs and tables of a Word document.
+type DOCX struct {
+	r io.ReaderAt
+	s int64
+}
+
+var _ Loader = DOCX{}
+
+// NewDOCX creates a new docx loader with an io.ReaderAt and the size of the document.
+func NewDOCX(r io.ReaderAt, size int64) DOCX {
+	return DOCX{
+		r: r,
+		s: size,
+	}
+}
+
+// Load reads the document and returns a single document with its text. Paragraphs are
+// separated by blank lines and the cells of table rows by " | ". The title and author
+// of the document are added to the metadata if they are set.
+func (d DOCX) Load(_ context.Context) ([]schema.Document, error) {
+	zr, err := zip.NewReader(d.r, d.s)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readZipFile(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	text, err := docxText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]any{}
+	if zipFileExists(zr, "docProps/core.xml") {
+		var core struct {
+			Title   string `xml:"title"`
+			Creator string `xml:"creator"`
+		}
+		if err := decodeZipXML(zr, "docProps/core.xml", &core); err != nil {
+			return nil, err
+		}
+		if core.Title != "" {
+			metadata["title"] = core.Title
+		}
+		if core.Creator != "" {
+			metadata["author"] = core.Creator
+		}
+	}
+
+	return []schema.Document{
+		{
+			PageContent: text,
+			Metadata:    metadata,
+		},
+	}, nil
+}
+
+// LoadAndSplit reads the document and splits its text into multiple documents using a
+// text splitter.
+func (d DOCX) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := d.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// docxText extracts the paragraphs and tables of the body of a word/document.xml file.
+// Nested tables are flattened into the cell containing them.
+func docxText(data []byte) (string, error) { //nolint:cyclop
+	var (
+		blocks    []string
+		paragraph strings.Builder
+		inText    bool
+		tables    int
+		rows      []string
+		row       []string
+		cell      []string
+	)
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				paragraph.Reset()
+			case "t":
+				inText = true
+			case "tab":
+				paragraph.WriteString("\t")
+			case "br", "cr":
+				paragraph.WriteString("\n")
+			case "tbl":
+				tables++
+				if tables == 1 {
+					rows = nil
+				}
+			case "tr":
+				if tables == 1 {
+					row = nil
+				}
+			case "tc":
+				if tables == 1 {
+					cell = nil
+				}
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				switch {
+				case text == "":
+				case tables > 0:
+					cell = append(cell, text)
+				default:
+					blocks = append(blocks, text)
+				}
+			case "tc":
+				if tables == 1 {
+					row = append(row, strings.Join(cell, " "))
+				}
+			case "tr":
+				if tables == 1 {
+					rows = append(rows, strings.Join(row, " | "))
+				}
+			case "tbl":
+				tables--
+				if tables == 0 && len(rows) > 0 {
+					blocks = append(blocks, strings.Join(rows, "\n"))
+				}
+			}
+		}
+	}
+
+	return strings.Join(blocks, "\n\n"), nil
+}
@@ -0,0 +1,31 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDOCXLoader(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("./testdata/sample.docx")
+	require.NoError(t, err)
+	defer f.Close()
+	finfo, err := f.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewDOCX(f, finfo.Size()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	expected := "Quarterly Report\n\n" +
+		"Revenue grew 12% this quarter.\n\n" +
+		"Region | Sales\nEMEA | 100\n\n" +
+		"First line\nSecond line"
+	assert.Equal(t, expected, docs[0].PageContent)
+	assert.Equal(t, map[string]any{"title": "Q3", "author": "Jane Doe"}, docs[0].Metadata)
+}
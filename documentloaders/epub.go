@@ -0,0 +1,232 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ErrInvalidEPUB is returned when an epub file has no package document.
+var ErrInvalidEPUB = errors.New("invalid epub: no package document")
+
+// EPUB loads the chapters of an EPUB book.
+type EPUB struct {
+	r io.ReaderAt
+	s int64
+}
+
+var _ LazyLoader = EPUB{}
+
+// NewEPUB creates a new epub loader with an io.ReaderAt and the size of the book.
+func NewEPUB(r io.ReaderAt, size int64) EPUB {
+	return EPUB{
+		r: r,
+		s: size,
+	}
+}
+
+// Load reads the book and returns a document with the text of every chapter in the
+// reading order. Chapters without text, like cover pages, are skipped. The metadata
+// holds the "title" and "author" of the book, and the "chapter" number, the
+// "chapter_title" and the "href" of the chapter.
+func (e EPUB) Load(ctx context.Context) ([]schema.Document, error) {
+	return collect(ctx, e.LoadLazy)
+}
+
+// LoadLazy reads the book and calls yield with the document of one chapter at a time.
+func (e EPUB) LoadLazy(ctx context.Context, yield func(schema.Document) error) error {
+	zr, err := zip.NewReader(e.r, e.s)
+	if err != nil {
+		return err
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeZipXML(zr, "META-INF/container.xml", &container); err != nil {
+		return err
+	}
+	if len(container.Rootfiles) == 0 {
+		return ErrInvalidEPUB
+	}
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg struct {
+		Title    string `xml:"metadata>title"`
+		Creator  string `xml:"metadata>creator"`
+		Manifest []struct {
+			ID   string `xml:"id,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"manifest>item"`
+		Spine []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"spine>itemref"`
+	}
+	if err := decodeZipXML(zr, opfPath, &pkg); err != nil {
+		return err
+	}
+
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = item.Href
+	}
+
+	chapter := 0
+	for _, ref := range pkg.Spine {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		href, ok := hrefs[ref.IDRef]
+		if !ok {
+			continue
+		}
+		name, err := url.PathUnescape(href)
+		if err != nil {
+			return err
+		}
+		data, err := readZipFile(zr, path.Join(path.Dir(opfPath), name))
+		if err != nil {
+			return err
+		}
+
+		title, text, err := epubChapterText(data)
+		if err != nil {
+			return err
+		}
+		if text == "" {
+			continue
+		}
+
+		chapter++
+		metadata := map[string]any{
+			"chapter":       chapter,
+			"chapter_title": title,
+			"href":          href,
+		}
+		if pkg.Title != "" {
+			metadata["title"] = strings.TrimSpace(pkg.Title)
+		}
+		if pkg.Creator != "" {
+			metadata["author"] = strings.TrimSpace(pkg.Creator)
+		}
+
+		if err := yield(schema.Document{PageContent: text, Metadata: metadata}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadAndSplit reads the book and splits the chapters using a text splitter.
+func (e EPUB) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := e.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// epubChapterText returns the title and the text of the body of a chapter document,
+// with one line per block element. The title is the first heading of the chapter,
+// or its title element if it has no heading.
+func epubChapterText(data []byte) (string, string, error) {
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return "", "", err
+	}
+
+	var title, heading string
+	var sb strings.Builder
+	var walk func(n *html.Node, inBody, inPre bool)
+	walk = func(n *html.Node, inBody, inPre bool) {
+		switch {
+		case n.Type == html.TextNode && inBody && inPre:
+			sb.WriteString(n.Data)
+			return
+		case n.Type == html.TextNode && inBody:
+			sb.WriteString(strings.ReplaceAll(n.Data, "\n", " "))
+			return
+		case n.Type != html.ElementNode && n.Type != html.DocumentNode:
+			return
+		}
+
+		switch n.DataAtom { //nolint:exhaustive
+		case atom.Title:
+			title = nodeText(n)
+			return
+		case atom.Script, atom.Style:
+			return
+		case atom.Body:
+			inBody = true
+		case atom.Pre:
+			inPre = true
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			if heading == "" {
+				heading = strings.TrimSpace(nodeText(n))
+			}
+		}
+
+		block := inBody && isBlockElement(n.DataAtom)
+		if block {
+			sb.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inBody, inPre)
+		}
+		if block {
+			sb.WriteString("\n")
+		}
+	}
+	walk(root, false, false)
+
+	lines := strings.Split(sb.String(), "\n")
+	text := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			text = append(text, line)
+		}
+	}
+
+	if heading == "" {
+		heading = strings.TrimSpace(title)
+	}
+	return heading, strings.Join(text, "\n"), nil
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(nodeText(c))
+	}
+	return sb.String()
+}
+
+func isBlockElement(a atom.Atom) bool {
+	switch a { //nolint:exhaustive
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Aside,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Li, atom.Dl, atom.Dt, atom.Dd,
+		atom.Table, atom.Tr, atom.Blockquote, atom.Pre, atom.Br, atom.Hr, atom.Figure, atom.Figcaption:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEPUBLoader(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("./testdata/sample.epub")
+	require.NoError(t, err)
+	defer f.Close()
+	finfo, err := f.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewEPUB(f, finfo.Size()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "It was a dark night.\nRain\nWind", docs[0].PageContent)
+	assert.Equal(t, map[string]any{
+		"title":         "A Sample Book",
+		"author":        "John Writer",
+		"chapter":       1,
+		"chapter_title": "Chapter One",
+		"href":          "text/chapter2.xhtml",
+	}, docs[0].Metadata)
+
+	assert.Equal(t, "The End\nThey lived happily ever after.", docs[1].PageContent)
+	assert.Equal(t, 2, docs[1].Metadata["chapter"])
+	assert.Equal(t, "The End", docs[1].Metadata["chapter_title"])
+	assert.Equal(t, "text/chapter%201.xhtml", docs[1].Metadata["href"])
+}
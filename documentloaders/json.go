@@ -0,0 +1,305 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// ErrInvalidJSONPath is returned when a path expression of the JSON loader can not be parsed.
+var ErrInvalidJSONPath = errors.New("invalid json path")
+
+// JSONMetadataFunc returns the metadata of the document of a record. It is called with
+// the record and the default metadata, holding the "seq_num" of the record. Numbers
+// in the record are json.Number values, so large integers keep their precision.
+type JSONMetadataFunc func(record any, metadata map[string]any) (map[string]any, error)
+
+// JSON loads documents from JSON or JSON Lines data.
+//
+// The records to load are chosen with a path expression in a subset of JSONPath:
+// "$" is the root value, ".name" or "['name']" a field of an object, "[n]" an
+// element of an array, negative indices counting from the end, and ".*" or "[*]"
+// all the fields or elements of a value. The content of a record is chosen with a
+// path expression relative to the record.
+type JSON struct {
+	r            io.Reader
+	jsonLines    bool
+	recordPath   string
+	contentPath  string
+	metadataFunc JSONMetadataFunc
+}
+
+var _ LazyLoader = JSON{}
+
+// JSONOptions are options for the JSON loader.
+type JSONOptions func(j *JSON)
+
+// WithJSONLines makes the loader read JSON Lines, or any stream of JSON values, one
+// value at a time. The record path is applied to every value.
+func WithJSONLines() JSONOptions {
+	return func(j *JSON) {
+		j.jsonLines = true
+	}
+}
+
+// WithRecordPath sets the path expression of the records to load, such as
+// "$.data.items[*]". The default is "$", loading the whole value as one record.
+func WithRecordPath(expr string) JSONOptions {
+	return func(j *JSON) {
+		j.recordPath = expr
+	}
+}
+
+// WithContentPath sets the path expression of the page content of a record, such as
+// "$.text". String values are used as they are and other values are encoded as JSON.
+// If the expression matches several values they are joined by newlines, and records
+// it matches nothing in are skipped. The default is "$", the whole record.
+func WithContentPath(expr string) JSONOptions {
+	return func(j *JSON) {
+		j.contentPath = expr
+	}
+}
+
+// WithMetadataFunc sets the function returning the metadata of the document of a record.
+func WithMetadataFunc(fn JSONMetadataFunc) JSONOptions {
+	return func(j *JSON) {
+		j.metadataFunc = fn
+	}
+}
+
+// NewJSON creates a new json loader with an io.Reader.
+func NewJSON(r io.Reader, opts ...JSONOptions) JSON {
+	j := JSON{
+		r:           r,
+		recordPath:  "$",
+		contentPath: "$",
+	}
+	for _, opt := range opts {
+		opt(&j)
+	}
+	return j
+}
+
+// Load reads from the io.Reader and returns a document for every record.
+func (j JSON) Load(ctx context.Context) ([]schema.Document, error) {
+	return collect(ctx, j.LoadLazy)
+}
+
+// LoadLazy reads from the io.Reader and calls yield with the document of every record.
+// JSON Lines are read one line at a time, while JSON data is decoded at once.
+func (j JSON) LoadLazy(ctx context.Context, yield func(schema.Document) error) error {
+	recordPath, err := parseJSONPath(j.recordPath)
+	if err != nil {
+		return err
+	}
+	contentPath, err := parseJSONPath(j.contentPath)
+	if err != nil {
+		return err
+	}
+
+	seq := 0
+	dec := json.NewDecoder(j.r)
+	dec.UseNumber()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var value any
+		err := dec.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, record := range recordPath.eval(value) {
+			seq++
+			doc, ok, err := j.document(record, contentPath, seq)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if err := yield(doc); err != nil {
+				return err
+			}
+		}
+
+		if !j.jsonLines {
+			return nil
+		}
+	}
+}
+
+// LoadAndSplit reads from the io.Reader and splits the documents using a text splitter.
+func (j JSON) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := j.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (j JSON) document(record any, contentPath jsonPath, seq int) (schema.Document, bool, error) {
+	values := contentPath.eval(record)
+	if len(values) == 0 {
+		return schema.Document{}, false, nil
+	}
+
+	content := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			content = append(content, s)
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return schema.Document{}, false, err
+		}
+		content = append(content, string(data))
+	}
+
+	metadata := map[string]any{"seq_num": seq}
+	if j.metadataFunc != nil {
+		var err error
+		metadata, err = j.metadataFunc(record, metadata)
+		if err != nil {
+			return schema.Document{}, false, err
+		}
+	}
+
+	return schema.Document{
+		PageContent: strings.Join(content, "\n"),
+		Metadata:    metadata,
+	}, true, nil
+}
+
+// jsonPathStep is a step of a path expression. A step with a nil field and a nil
+// index matches every field or element.
+type jsonPathStep struct {
+	field *string
+	index *int
+}
+
+type jsonPath []jsonPathStep
+
+// parseJSONPath parses a path expression. The leading "$" is optional.
+func parseJSONPath(expr string) (jsonPath, error) { //nolint:cyclop
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var path jsonPath
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			name := rest[1:end]
+			if name == "" || strings.Contains(name, "]") {
+				return nil, fmt.Errorf("%w: %q: invalid field name %q", ErrInvalidJSONPath, expr, name)
+			}
+			if name == "*" {
+				path = append(path, jsonPathStep{})
+			} else {
+				path = append(path, jsonPathStep{field: &name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q: missing ]", ErrInvalidJSONPath, expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "*":
+				path = append(path, jsonPathStep{})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				name := inner[1 : len(inner)-1]
+				path = append(path, jsonPathStep{field: &name})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %q: invalid index %q", ErrInvalidJSONPath, expr, inner)
+				}
+				path = append(path, jsonPathStep{index: &index})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%w: %q: unexpected %q", ErrInvalidJSONPath, expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// eval returns the values the path matches in value, in document order. The fields
+// matched by a wildcard are ordered by name.
+func (p jsonPath) eval(value any) []any {
+	values := []any{value}
+	for _, step := range p {
+		var next []any
+		for _, v := range values {
+			next = append(next, step.eval(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s jsonPathStep) eval(value any) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		if s.field != nil {
+			if field, ok := v[*s.field]; ok {
+				return []any{field}
+			}
+			return nil
+		}
+		if s.index != nil {
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]any, 0, len(keys))
+		for _, key := range keys {
+			fields = append(fields, v[key])
+		}
+		return fields
+	case []any:
+		if s.field != nil {
+			return nil
+		}
+		if s.index != nil {
+			i := *s.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return nil
+			}
+			return []any{v[i]}
+		}
+		return v
+	default:
+		return nil
+	}
+}
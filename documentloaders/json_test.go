@@ -0,0 +1,94 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLoader(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/test.json")
+	require.NoError(t, err)
+	defer file.Close()
+
+	loader := NewJSON(file,
+		WithRecordPath("$.data.items[*]"),
+		WithContentPath(".body"),
+		WithMetadataFunc(func(record any, metadata map[string]any) (map[string]any, error) {
+			item := record.(map[string]any) //nolint:forcetypeassert
+			metadata["title"] = item["title"]
+			return metadata, nil
+		}),
+	)
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "Hello world", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"seq_num": 1, "title": "First"}, docs[0].Metadata)
+	assert.Equal(t, "Goodbye", docs[1].PageContent)
+	assert.Equal(t, map[string]any{"seq_num": 2, "title": "Second"}, docs[1].Metadata)
+}
+
+func TestJSONLoaderLines(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/test.jsonl")
+	require.NoError(t, err)
+	defer file.Close()
+
+	docs, err := NewJSON(file, WithJSONLines(), WithContentPath("$['msg']")).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "server started", docs[0].PageContent)
+	assert.Equal(t, "connection refused", docs[1].PageContent)
+	assert.Equal(t, 2, docs[1].Metadata["seq_num"])
+}
+
+func TestJSONLoaderContent(t *testing.T) {
+	t.Parallel()
+
+	data := `{"items": [{"tags": ["a", "b"], "n": 1.5}, {"tags": ["c"], "n": 2}], "id": 12345678901234567890}`
+
+	cases := []struct {
+		records string
+		content string
+		want    []string
+	}{
+		{"$", "$", []string{`{"id":12345678901234567890,"items":[{"n":1.5,"tags":["a","b"]},{"n":2,"tags":["c"]}]}`}},
+		{"items[*]", "n", []string{"1.5", "2"}},
+		{"items[-1]", "tags", []string{`["c"]`}},
+		{"items.*", "tags[*]", []string{"a\nb", "c"}},
+		{"$.items[0]", "$.*", []string{"1.5\n[\"a\",\"b\"]"}},
+		{"$.missing[*]", "$", nil},
+		{"$", "$.id", []string{"12345678901234567890"}},
+	}
+
+	for _, c := range cases {
+		docs, err := NewJSON(strings.NewReader(data), WithRecordPath(c.records), WithContentPath(c.content)).
+			Load(context.Background())
+		require.NoError(t, err)
+
+		var contents []string
+		for _, doc := range docs {
+			contents = append(contents, doc.PageContent)
+		}
+		assert.Equal(t, c.want, contents, "%s %s", c.records, c.content)
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"$.", "$.a[", "$.a[x]", "$a]"} {
+		_, err := parseJSONPath(expr)
+		require.ErrorIs(t, err, ErrInvalidJSONPath, expr)
+	}
+}
@@ -0,0 +1,88 @@
+package documentloaders
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"sigs.k8s.io/yaml"
+)
+
+// Markdown loads a markdown document from an io.Reader.
+type Markdown struct {
+	r io.Reader
+}
+
+var _ Loader = Markdown{}
+
+// NewMarkdown creates a new markdown loader with an io.Reader.
+func NewMarkdown(r io.Reader) Markdown {
+	return Markdown{
+		r: r,
+	}
+}
+
+// Load reads from the io.Reader and returns a single document with the markdown. The
+// fields of a YAML front matter, delimited by "---" lines at the start of the
+// document, are parsed into the metadata and removed from the content. Values are
+// decoded as in JSON, so numbers are float64 and dates are strings.
+func (m Markdown) Load(_ context.Context) ([]schema.Document, error) {
+	data, err := io.ReadAll(m.r)
+	if err != nil {
+		return nil, err
+	}
+
+	content, frontMatter := splitFrontMatter(strings.TrimPrefix(string(data), "\ufeff"))
+	metadata := map[string]any{}
+	if frontMatter != "" {
+		if err := yaml.Unmarshal([]byte(frontMatter), &metadata); err != nil {
+			return nil, fmt.Errorf("front matter: %w", err)
+		}
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+	}
+
+	return []schema.Document{
+		{
+			PageContent: content,
+			Metadata:    metadata,
+		},
+	}, nil
+}
+
+// LoadAndSplit reads markdown from the io.Reader and splits it into multiple documents
+// using a text splitter.
+func (m Markdown) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := m.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// splitFrontMatter returns the content of a markdown document and its front matter.
+// Documents without a closed front matter are returned as they are.
+func splitFrontMatter(text string) (string, string) {
+	normalized := strings.ReplaceAll(text, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return text, ""
+	}
+
+	lines := strings.SplitAfter(normalized[len("---\n"):], "\n")
+	offset := len("---\n")
+	for i, line := range lines {
+		if trimmed := strings.TrimRight(line, " \t\n"); trimmed == "---" || trimmed == "..." {
+			frontMatter := normalized[len("---\n"):offset]
+			content := strings.Join(lines[i+1:], "")
+			return strings.TrimLeft(content, "\n"), frontMatter
+		}
+		offset += len(line)
+	}
+
+	return text, ""
+}
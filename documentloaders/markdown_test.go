@@ -0,0 +1,76 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownLoader(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/test.md")
+	require.NoError(t, err)
+	defer file.Close()
+
+	docs, err := NewMarkdown(file).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	assert.Equal(t, "# Getting Started\n\nInstall the package.\n", docs[0].PageContent)
+	assert.Equal(t, map[string]any{
+		"title":  "Getting Started",
+		"tags":   []any{"intro", "setup"},
+		"weight": float64(2),
+	}, docs[0].Metadata)
+}
+
+func TestMarkdownLoaderFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		text     string
+		content  string
+		metadata map[string]any
+	}{
+		{
+			name:     "no front matter",
+			text:     "# Title\n---\nfoo: bar\n",
+			content:  "# Title\n---\nfoo: bar\n",
+			metadata: map[string]any{},
+		},
+		{
+			name:     "unclosed front matter",
+			text:     "---\nfoo: bar\n",
+			content:  "---\nfoo: bar\n",
+			metadata: map[string]any{},
+		},
+		{
+			name:     "empty front matter",
+			text:     "---\n---\ntext",
+			content:  "text",
+			metadata: map[string]any{},
+		},
+		{
+			name:     "crlf and dots",
+			text:     "---\r\nfoo: bar\r\n...\r\ntext",
+			content:  "text",
+			metadata: map[string]any{"foo": "bar"},
+		},
+	}
+
+	for _, c := range cases {
+		docs, err := NewMarkdown(strings.NewReader(c.text)).Load(context.Background())
+		require.NoError(t, err, c.name)
+		assert.Equal(t, c.content, docs[0].PageContent, c.name)
+		assert.Equal(t, c.metadata, docs[0].Metadata, c.name)
+	}
+
+	_, err := NewMarkdown(strings.NewReader("---\nfoo: [\n---\n")).Load(context.Background())
+	require.Error(t, err)
+}
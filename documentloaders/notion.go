@@ -1,10 +1,12 @@
 package documentloaders
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 
 	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
 )
 
 // NotionDirectoryLoader is a document loader that reads content from pages within a Notion Database.
@@ -13,6 +15,8 @@ type NotionDirectoryLoader struct {
 	encoding string
 }
 
+var _ Loader = &NotionDirectoryLoader{}
+
 // NewNotionDirectory creates a new NotionDirectoryLoader with the given file path and encoding.
 func NewNotionDirectory(filePath string, encoding ...string) *NotionDirectoryLoader {
 	defaultEncoding := "utf-8"
@@ -31,7 +35,7 @@ func NewNotionDirectory(filePath string, encoding ...string) *NotionDirectoryLoa
 }
 
 // Load retrieves data from a Notion directory and returns a list of schema.Document objects.
-func (n *NotionDirectoryLoader) Load() ([]schema.Document, error) {
+func (n *NotionDirectoryLoader) Load(_ context.Context) ([]schema.Document, error) {
 	files, err := os.ReadDir(n.filePath)
 	if err != nil {
 		return nil, err
@@ -55,3 +59,16 @@ func (n *NotionDirectoryLoader) Load() ([]schema.Document, error) {
 
 	return documents, nil
 }
+
+// LoadAndSplit retrieves data from a Notion directory and splits the documents using a text splitter.
+func (n *NotionDirectoryLoader) LoadAndSplit(
+	ctx context.Context,
+	splitter textsplitter.TextSplitter,
+) ([]schema.Document, error) {
+	docs, err := n.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
@@ -16,7 +16,7 @@ type PDF struct {
 	password string
 }
 
-var _ Loader = PDF{}
+var _ LazyLoader = PDF{}
 
 // PDFOptions are options for the PDF loader.
 type PDFOptions func(pdf *PDF)
@@ -51,29 +51,37 @@ func (p *PDF) getPassword() string {
 
 // Load reads from the io.Reader for the PDF data and returns the documents with the data and with
 // metadata attached of the page number and total number of pages of the PDF.
-func (p PDF) Load(_ context.Context) ([]schema.Document, error) {
+func (p PDF) Load(ctx context.Context) ([]schema.Document, error) {
+	return collect(ctx, p.LoadLazy)
+}
+
+// LoadLazy reads from the io.Reader for the PDF data and calls yield with the document of
+// every page, extracting the text of one page at a time.
+func (p PDF) LoadLazy(ctx context.Context, yield func(schema.Document) error) error {
 	var reader *pdf.Reader
 	var err error
 
 	if p.password != "" {
 		reader, err = pdf.NewReaderEncrypted(p.r, p.s, p.getPassword)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	} else {
 		reader, err = pdf.NewReader(p.r, p.s)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	numPages := reader.NumPage()
 
-	docs := []schema.Document{}
-
 	// fonts to be used when getting plain text from pages
 	fonts := make(map[string]*pdf.Font)
 	for i := 1; i < numPages+1; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		p := reader.Page(i)
 		// add fonts to map
 		for _, name := range p.Fonts() {
@@ -85,20 +93,22 @@ func (p PDF) Load(_ context.Context) ([]schema.Document, error) {
 		}
 		text, err := p.GetPlainText(fonts)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		// add the document to the doc list
-		docs = append(docs, schema.Document{
+		err = yield(schema.Document{
 			PageContent: text,
 			Metadata: map[string]any{
 				"page":        i,
 				"total_pages": numPages,
 			},
 		})
+		if err != nil {
+			return err
+		}
 	}
 
-	return docs, nil
+	return nil
 }
 
 // LoadAndSplit reads pdf data from the io.Reader and splits it into multiple
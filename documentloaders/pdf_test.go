@@ -2,12 +2,14 @@ package documentloaders
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/ledongthuc/pdf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/textsplitter"
 )
 
@@ -138,3 +140,22 @@ func TestPDFTextSplit(t *testing.T) {
 		}
 	})
 }
+
+func TestPDFLoadLazy(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("./testdata/sample.pdf")
+	require.NoError(t, err)
+	defer f.Close()
+	finfo, err := f.Stat()
+	require.NoError(t, err)
+
+	var pages []any
+	errStop := errors.New("stop")
+	err = NewPDF(f, finfo.Size()).LoadLazy(context.Background(), func(doc schema.Document) error {
+		pages = append(pages, doc.Metadata["page"])
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	assert.Equal(t, []any{1}, pages)
+}
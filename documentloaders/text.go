@@ -14,7 +14,7 @@ type Text struct {
 	r io.Reader
 }
 
-var _ Loader = Text{}
+var _ LazyLoader = Text{}
 
 // NewText creates a new text loader with an io.Reader.
 func NewText(r io.Reader) Text {
@@ -24,19 +24,22 @@ func NewText(r io.Reader) Text {
 }
 
 // Load reads from the io.Reader and returns a single document with the data.
-func (l Text) Load(_ context.Context) ([]schema.Document, error) {
+func (l Text) Load(ctx context.Context) ([]schema.Document, error) {
+	return collect(ctx, l.LoadLazy)
+}
+
+// LoadLazy reads from the io.Reader and calls yield with a single document with the data.
+func (l Text) LoadLazy(_ context.Context, yield func(schema.Document) error) error {
 	buf := new(bytes.Buffer)
 	_, err := io.Copy(buf, l.r)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return []schema.Document{
-		{
-			PageContent: buf.String(),
-			Metadata:    map[string]any{},
-		},
-	}, nil
+	return yield(schema.Document{
+		PageContent: buf.String(),
+		Metadata:    map[string]any{},
+	})
 }
 
 // LoadAndSplit reads text data from the io.Reader and splits it into multiple
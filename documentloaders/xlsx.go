@@ -0,0 +1,341 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/exp/slices"
+)
+
+// XLSX loads the sheets of an Excel workbook.
+type XLSX struct {
+	r      io.ReaderAt
+	s      int64
+	sheets []string
+	perRow bool
+}
+
+var _ LazyLoader = XLSX{}
+
+// XLSXOptions are options for the XLSX loader.
+type XLSXOptions func(x *XLSX)
+
+// WithSheets sets the names of the sheets to load. By default all sheets are loaded.
+func WithSheets(names ...string) XLSXOptions {
+	return func(x *XLSX) {
+		x.sheets = names
+	}
+}
+
+// WithDocumentPerRow makes the loader return a document for every row of a sheet like
+// the CSV loader, using the first row as the header, instead of one per sheet.
+func WithDocumentPerRow() XLSXOptions {
+	return func(x *XLSX) {
+		x.perRow = true
+	}
+}
+
+// NewXLSX creates a new xlsx loader with an io.ReaderAt and the size of the workbook.
+func NewXLSX(r io.ReaderAt, size int64, opts ...XLSXOptions) XLSX {
+	x := XLSX{
+		r: r,
+		s: size,
+	}
+	for _, opt := range opts {
+		opt(&x)
+	}
+	return x
+}
+
+// Load reads the workbook and returns a document for every sheet, with the rows of
+// the sheet as CSV, or a document for every row. The metadata holds the name of the
+// "sheet" and, for documents per row, the "row" number in the sheet.
+func (x XLSX) Load(ctx context.Context) ([]schema.Document, error) {
+	return collect(ctx, x.LoadLazy)
+}
+
+// LoadLazy reads the workbook and calls yield with the documents of one sheet at a time.
+func (x XLSX) LoadLazy(ctx context.Context, yield func(schema.Document) error) error {
+	zr, err := zip.NewReader(x.r, x.s)
+	if err != nil {
+		return err
+	}
+
+	sheets, err := xlsxSheets(zr)
+	if err != nil {
+		return err
+	}
+	shared, err := xlsxSharedStrings(zr)
+	if err != nil {
+		return err
+	}
+
+	for _, sheet := range sheets {
+		if len(x.sheets) > 0 && !slices.Contains(x.sheets, sheet.name) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rows, err := xlsxRows(zr, sheet.path, shared)
+		if err != nil {
+			return err
+		}
+
+		if x.perRow {
+			err = yieldXLSXRows(sheet.name, rows, yield)
+		} else {
+			err = yieldXLSXSheet(sheet.name, rows, yield)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadAndSplit reads the workbook and splits the documents using a text splitter.
+func (x XLSX) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := x.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func yieldXLSXSheet(name string, rows []xlsxRow, yield func(schema.Document) error) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for _, row := range rows {
+		if err := w.Write(row.values); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	return yield(schema.Document{
+		PageContent: strings.TrimSuffix(buf.String(), "\n"),
+		Metadata:    map[string]any{"sheet": name},
+	})
+}
+
+func yieldXLSXRows(name string, rows []xlsxRow, yield func(schema.Document) error) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	header := rows[0].values
+	for _, row := range rows[1:] {
+		content := make([]string, 0, len(row.values))
+		for j, value := range row.values {
+			column := xlsxColumnName(j)
+			if j < len(header) && header[j] != "" {
+				column = header[j]
+			}
+			content = append(content, fmt.Sprintf("%s: %s", column, value))
+		}
+
+		err := yield(schema.Document{
+			PageContent: strings.Join(content, "\n"),
+			Metadata:    map[string]any{"sheet": name, "row": row.number},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type xlsxSheet struct {
+	name string
+	path string
+}
+
+// xlsxSheets returns the sheets of a workbook in order with the paths of their files.
+func xlsxSheets(zr *zip.Reader) ([]xlsxSheet, error) {
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(zr, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		target := strings.TrimPrefix(rel.Target, "/")
+		if !strings.HasPrefix(rel.Target, "/") {
+			target = path.Join("xl", rel.Target)
+		}
+		targets[rel.ID] = target
+	}
+
+	sheets := make([]xlsxSheet, 0, len(workbook.Sheets))
+	for _, sheet := range workbook.Sheets {
+		target, ok := targets[sheet.ID]
+		if !ok {
+			return nil, fmt.Errorf("no file for sheet %q", sheet.Name)
+		}
+		sheets = append(sheets, xlsxSheet{name: sheet.Name, path: target})
+	}
+
+	return sheets, nil
+}
+
+// xlsxRichText is a string of a workbook, made of runs if it is formatted.
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var sb strings.Builder
+	for _, r := range t.R {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+// xlsxSharedStrings returns the shared strings table of a workbook, if it has one.
+func xlsxSharedStrings(zr *zip.Reader) ([]string, error) {
+	if !zipFileExists(zr, "xl/sharedStrings.xml") {
+		return nil, nil
+	}
+
+	var sst struct {
+		Items []xlsxRichText `xml:"si"`
+	}
+	if err := decodeZipXML(zr, "xl/sharedStrings.xml", &sst); err != nil {
+		return nil, err
+	}
+
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+// xlsxRow is a row of a sheet with its one based number in the sheet.
+type xlsxRow struct {
+	number int
+	values []string
+}
+
+// xlsxRows returns the non empty rows of a sheet. Cells are placed in the column of
+// their reference, so missing cells are empty values.
+func xlsxRows(zr *zip.Reader, name string, shared []string) ([]xlsxRow, error) {
+	var sheet struct {
+		Rows []struct {
+			Number int `xml:"r,attr"`
+			Cells  []struct {
+				Ref    string       `xml:"r,attr"`
+				Type   string       `xml:"t,attr"`
+				Value  string       `xml:"v"`
+				Inline xlsxRichText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(zr, name, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]xlsxRow, 0, len(sheet.Rows))
+	number := 0
+	for _, r := range sheet.Rows {
+		// rows without a number follow the previous row
+		number++
+		if r.Number > 0 {
+			number = r.Number
+		}
+
+		var row []string
+		for i, c := range r.Cells {
+			column := i
+			if c.Ref != "" {
+				column = xlsxColumnIndex(c.Ref)
+			}
+			if column < 0 {
+				return nil, fmt.Errorf("%s: invalid cell reference %q", name, c.Ref)
+			}
+			for len(row) <= column {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				idx, err := strconv.Atoi(c.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("%s: invalid shared string %q", name, c.Value)
+				}
+				row[column] = shared[idx]
+			case "inlineStr":
+				row[column] = c.Inline.String()
+			case "b":
+				row[column] = strconv.FormatBool(c.Value == "1")
+			default:
+				row[column] = c.Value
+			}
+		}
+
+		if strings.Join(row, "") != "" {
+			rows = append(rows, xlsxRow{number: number, values: row})
+		}
+	}
+
+	return rows, nil
+}
+
+// xlsxColumnIndex returns the zero based column index of a cell reference like "AB12",
+// or -1 if the reference has no column.
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+	}
+	return index - 1
+}
+
+// xlsxColumnName returns the name of the column with the zero based index, like "AB".
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXLSXLoader(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("./testdata/sample.xlsx")
+	require.NoError(t, err)
+	defer f.Close()
+	finfo, err := f.Stat()
+	require.NoError(t, err)
+
+	t.Run("per sheet", func(t *testing.T) {
+		docs, err := NewXLSX(f, finfo.Size()).Load(context.Background())
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+
+		assert.Equal(t, "name,age,active\nJohn Doe,25,true\nJane Smith,,false", docs[0].PageContent)
+		assert.Equal(t, map[string]any{"sheet": "People"}, docs[0].Metadata)
+		assert.Equal(t, `,"Remember, the milk"`, docs[1].PageContent)
+		assert.Equal(t, map[string]any{"sheet": "Notes"}, docs[1].Metadata)
+	})
+
+	t.Run("per row", func(t *testing.T) {
+		docs, err := NewXLSX(f, finfo.Size(), WithSheets("People"), WithDocumentPerRow()).
+			Load(context.Background())
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+
+		assert.Equal(t, "name: John Doe\nage: 25\nactive: true", docs[0].PageContent)
+		assert.Equal(t, map[string]any{"sheet": "People", "row": 2}, docs[0].Metadata)
+		assert.Equal(t, "name: Jane Smith\nage: \nactive: false", docs[1].PageContent)
+		assert.Equal(t, map[string]any{"sheet": "People", "row": 4}, docs[1].Metadata)
+	})
+}
+
+func TestXLSXColumns(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		ref   string
+		name  string
+		index int
+	}{
+		{"A1", "A", 0},
+		{"Z9", "Z", 25},
+		{"AA10", "AA", 26},
+		{"AB3", "AB", 27},
+		{"BA1", "BA", 52},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.index, xlsxColumnIndex(c.ref), c.ref)
+		assert.Equal(t, c.name, xlsxColumnName(c.index), c.ref)
+	}
+	assert.Equal(t, -1, xlsxColumnIndex("12"))
+}
+
+func TestXLSXInvalidCellReference(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`<worksheet><sheetData><row r="1"><c r="12"><v>1</v></c></row></sheetData></worksheet>`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	_, err = xlsxRows(zr, "xl/worksheets/sheet1.xml", nil)
+	require.ErrorContains(t, err, `invalid cell reference "12"`)
+}
@@ -0,0 +1,40 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// readZipFile returns the content of the file name of a zip archive.
+func readZipFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// decodeZipXML decodes the xml file name of a zip archive into v.
+func decodeZipXML(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer f.Close()
+
+	if err := xml.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// zipFileExists reports whether the zip archive contains the file name.
+func zipFileExists(zr *zip.Reader, name string) bool {
+	_, err := fs.Stat(zr, name)
+	return err == nil
+}
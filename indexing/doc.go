@@ -0,0 +1,14 @@
+/*
+Package indexing syncs documents into vector stores without adding the same
+document twice.
+
+The main components of this package are:
+
+- Manager: adds the documents that are not in a vector store yet and removes those
+that are no longer part of their source, as set by the CleanupMode.
+- RecordManager interface: keeps track of the hashes and the source ids of the
+documents added to a vector store.
+- InMemoryRecordManager: a RecordManager that keeps records in memory. The sqlite3
+and postgres subpackages persist records in a database.
+*/
+package indexing
@@ -0,0 +1,359 @@
+package indexing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const _defaultBatchSize = 100
+
+var (
+	// ErrInvalidCleanupMode is returned for unknown cleanup modes.
+	ErrInvalidCleanupMode = errors.New("invalid cleanup mode")
+	// ErrNoSourceID is returned when a document has no source id in the incremental
+	// cleanup mode.
+	ErrNoSourceID = errors.New("document has no source id")
+	// ErrDeleteNotSupported is returned when documents have to be deleted from a vector
+	// store that does not implement vectorstores.Deleter, or that did not return the
+	// ids of the documents when they were added.
+	ErrDeleteNotSupported = errors.New("vector store does not support deleting documents")
+	// ErrMismatchedIDs is returned when a vector store returns ids, but not one for
+	// every document added. The documents are recorded without ids.
+	ErrMismatchedIDs = errors.New("vector store returned a wrong number of ids")
+)
+
+// CleanupMode is the way documents that are no longer indexed are removed from the
+// vector store.
+type CleanupMode string
+
+const (
+	// CleanupNone never removes documents.
+	CleanupNone CleanupMode = "none"
+	// CleanupIncremental removes the documents of every source indexed that are not
+	// part of the source anymore, as the documents of the source are indexed.
+	CleanupIncremental CleanupMode = "incremental"
+	// CleanupFull removes all the documents that were not indexed in this run once
+	// all documents are indexed, including those of sources that disappeared.
+	CleanupFull CleanupMode = "full"
+)
+
+// SourceIDFunc returns the id of the source of a document, or "" if it has none.
+type SourceIDFunc func(doc schema.Document) string
+
+// Result is the outcome of indexing documents.
+type Result struct {
+	// NumAdded is the number of documents added to the vector store.
+	NumAdded int
+	// NumSkipped is the number of documents already in the vector store.
+	NumSkipped int
+	// NumDeleted is the number of documents removed from the vector store.
+	NumDeleted int
+}
+
+// Manager syncs documents into a vector store. It records the hash of every document
+// it adds in a record manager, skips the documents that have not changed since they
+// were added and removes documents as set by the cleanup mode.
+//
+// Documents are identified by the hash of their content and metadata, so a document
+// that changed is added again and its old version is only removed by a cleanup.
+type Manager struct {
+	store         vectorstores.VectorStore
+	records       RecordManager
+	cleanup       CleanupMode
+	sourceIDFunc  SourceIDFunc
+	batchSize     int
+	vectorOptions []vectorstores.Option
+}
+
+// Option is a function for creating a new manager with other than the default values.
+type Option func(m *Manager)
+
+// WithCleanup sets the cleanup mode. The default is CleanupNone.
+func WithCleanup(mode CleanupMode) Option {
+	return func(m *Manager) {
+		m.cleanup = mode
+	}
+}
+
+// WithSourceIDKey sets the metadata key of the source id of documents. The default
+// is "source", which the document loaders set.
+func WithSourceIDKey(key string) Option {
+	return func(m *Manager) {
+		m.sourceIDFunc = metadataSourceID(key)
+	}
+}
+
+// WithSourceIDFunc sets the function returning the source id of documents.
+func WithSourceIDFunc(fn SourceIDFunc) Option {
+	return func(m *Manager) {
+		m.sourceIDFunc = fn
+	}
+}
+
+// WithBatchSize sets the number of documents added to the vector store at once.
+// The default is 100.
+func WithBatchSize(size int) Option {
+	return func(m *Manager) {
+		m.batchSize = size
+	}
+}
+
+// WithVectorStoreOptions sets the options documents are added to the vector store with.
+func WithVectorStoreOptions(options ...vectorstores.Option) Option {
+	return func(m *Manager) {
+		m.vectorOptions = options
+	}
+}
+
+// NewManager creates a new manager indexing documents into the vector store. The
+// record manager must only be used for this vector store. Cleanups other than
+// CleanupNone need a vector store that implements vectorstores.Deleter.
+func NewManager(store vectorstores.VectorStore, records RecordManager, opts ...Option) (*Manager, error) {
+	m := &Manager{
+		store:        store,
+		records:      records,
+		cleanup:      CleanupNone,
+		sourceIDFunc: metadataSourceID("source"),
+		batchSize:    _defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	switch m.cleanup {
+	case CleanupNone, CleanupIncremental, CleanupFull:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCleanupMode, m.cleanup)
+	}
+	if m.batchSize <= 0 {
+		m.batchSize = _defaultBatchSize
+	}
+
+	return m, nil
+}
+
+// Index syncs the documents into the vector store.
+func (m *Manager) Index(ctx context.Context, docs []schema.Document) (Result, error) {
+	return m.index(ctx, func(ctx context.Context, yield func(schema.Document) error) error {
+		for _, doc := range docs {
+			if err := yield(doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IndexLoader syncs the documents of the loader into the vector store, loading them
+// a batch at a time.
+func (m *Manager) IndexLoader(ctx context.Context, loader documentloaders.LazyLoader) (Result, error) {
+	return m.index(ctx, loader.LoadLazy)
+}
+
+func (m *Manager) index(
+	ctx context.Context,
+	load func(ctx context.Context, yield func(schema.Document) error) error,
+) (Result, error) {
+	var result Result
+	start := time.Now()
+
+	batch := make([]schema.Document, 0, m.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := m.indexBatch(ctx, batch, start, &result)
+		batch = batch[:0]
+		return err
+	}
+
+	err := load(ctx, func(doc schema.Document) error {
+		batch = append(batch, doc)
+		if len(batch) < m.batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return result, err
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	if m.cleanup == CleanupFull {
+		stale, err := m.records.List(ctx, ListOptions{Before: start})
+		if err != nil {
+			return result, err
+		}
+		if err := m.delete(ctx, stale, &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// indexBatch adds the documents of the batch that are not indexed yet and marks the
+// others as indexed at the start time.
+func (m *Manager) indexBatch(ctx context.Context, batch []schema.Document, start time.Time, result *Result) error {
+	keys := make([]string, 0, len(batch))
+	docs := make(map[string]schema.Document, len(batch))
+	groups := make(map[string]string, len(batch))
+	sourceIDs := make([]string, 0)
+	seenSources := make(map[string]bool)
+
+	for _, doc := range batch {
+		key, err := documentKey(doc)
+		if err != nil {
+			return err
+		}
+		sourceID := m.sourceIDFunc(doc)
+		if sourceID == "" && m.cleanup == CleanupIncremental {
+			return fmt.Errorf("%w: %.40q", ErrNoSourceID, doc.PageContent)
+		}
+		if sourceID != "" && !seenSources[sourceID] {
+			seenSources[sourceID] = true
+			sourceIDs = append(sourceIDs, sourceID)
+		}
+
+		if _, ok := docs[key]; ok {
+			result.NumSkipped++
+			continue
+		}
+		keys = append(keys, key)
+		docs[key] = doc
+		groups[key] = sourceID
+	}
+
+	existing, err := m.records.Get(ctx, keys)
+	if err != nil {
+		return err
+	}
+	indexed := make(map[string]Record, len(existing))
+	for _, record := range existing {
+		indexed[record.Key] = record
+	}
+
+	records := make([]Record, 0, len(keys))
+	newKeys := make([]string, 0, len(keys))
+	newDocs := make([]schema.Document, 0, len(keys))
+	for _, key := range keys {
+		if record, ok := indexed[key]; ok {
+			record.GroupID = groups[key]
+			record.UpdatedAt = start
+			records = append(records, record)
+			result.NumSkipped++
+			continue
+		}
+		newKeys = append(newKeys, key)
+		newDocs = append(newDocs, docs[key])
+	}
+
+	var idsErr error
+	if len(newDocs) > 0 {
+		ids, err := m.store.AddDocuments(ctx, newDocs, m.vectorOptions...)
+		if err != nil {
+			return err
+		}
+		// the documents are written, so they are recorded even when their ids are
+		// unknown
+		if len(ids) != 0 && len(ids) != len(newDocs) {
+			idsErr = fmt.Errorf("%w: %d ids for %d documents", ErrMismatchedIDs, len(ids), len(newDocs))
+		}
+		for i, key := range newKeys {
+			record := Record{Key: key, GroupID: groups[key], UpdatedAt: start}
+			if len(ids) == len(newDocs) {
+				record.IDs = []string{ids[i]}
+			}
+			records = append(records, record)
+		}
+		result.NumAdded += len(newDocs)
+	}
+
+	if err := m.records.Update(ctx, records); err != nil {
+		return err
+	}
+	if idsErr != nil {
+		return idsErr
+	}
+
+	if m.cleanup == CleanupIncremental && len(sourceIDs) > 0 {
+		stale, err := m.records.List(ctx, ListOptions{Before: start, GroupIDs: sourceIDs})
+		if err != nil {
+			return err
+		}
+		return m.delete(ctx, stale, result)
+	}
+	return nil
+}
+
+// delete removes the documents of the records from the vector store and then the
+// records from the record manager.
+func (m *Manager) delete(ctx context.Context, records []Record, result *Result) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	deleter, ok := m.store.(vectorstores.Deleter)
+	if !ok {
+		return ErrDeleteNotSupported
+	}
+
+	ids := make([]string, 0, len(records))
+	keys := make([]string, 0, len(records))
+	for _, record := range records {
+		// the document of a record without ids can not be found in the store, so
+		// the record is kept
+		if len(record.IDs) == 0 {
+			return fmt.Errorf("%w: no ids recorded for document %s", ErrDeleteNotSupported, record.Key)
+		}
+		ids = append(ids, record.IDs...)
+		keys = append(keys, record.Key)
+	}
+	if err := deleter.Delete(ctx, ids); err != nil {
+		return err
+	}
+	if err := m.records.Delete(ctx, keys); err != nil {
+		return err
+	}
+	result.NumDeleted += len(records)
+	return nil
+}
+
+// documentKey returns the hex encoded SHA-256 hash of the content and the metadata
+// of a document.
+func documentKey(doc schema.Document) (string, error) {
+	// maps are encoded with sorted keys, so equal documents have the same hash
+	data, err := json.Marshal(struct {
+		Content  string         `json:"content"`
+		Metadata map[string]any `json:"metadata"`
+	}{doc.PageContent, doc.Metadata})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func metadataSourceID(key string) SourceIDFunc {
+	return func(doc schema.Document) string {
+		value, ok := doc.Metadata[key]
+		if !ok || value == nil {
+			return ""
+		}
+		if s, ok := value.(string); ok {
+			return s
+		}
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,213 @@
+package indexing_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/indexing"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// memoryStore is a vector store keeping the content of documents by id.
+type memoryStore struct {
+	docs   map[string]string
+	nextID int
+	added  int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{docs: make(map[string]string)}
+}
+
+func (s *memoryStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		s.nextID++
+		id := fmt.Sprintf("id-%d", s.nextID)
+		s.docs[id] = doc.PageContent
+		ids = append(ids, id)
+	}
+	s.added += len(docs)
+	return ids, nil
+}
+
+func (s *memoryStore) SimilaritySearch(context.Context, string, int, ...vectorstores.Option) ([]schema.Document, error) {
+	return nil, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+func (s *memoryStore) contents() []string {
+	contents := make([]string, 0, len(s.docs))
+	for _, content := range s.docs {
+		contents = append(contents, content)
+	}
+	sort.Strings(contents)
+	return contents
+}
+
+func doc(content, source string) schema.Document {
+	return schema.Document{PageContent: content, Metadata: map[string]any{"source": source}}
+}
+
+func TestManagerCleanupNone(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newMemoryStore()
+	m, err := indexing.NewManager(store, indexing.NewInMemoryRecordManager())
+	require.NoError(t, err)
+
+	docs := []schema.Document{doc("a", "1"), doc("b", "1"), doc("a", "1")}
+	result, err := m.Index(ctx, docs)
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 2, NumSkipped: 1}, result)
+
+	result, err = m.Index(ctx, []schema.Document{doc("a", "1"), doc("c", "1")})
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 1, NumSkipped: 1}, result)
+	assert.Equal(t, []string{"a", "b", "c"}, store.contents())
+}
+
+func TestManagerCleanupIncremental(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newMemoryStore()
+	m, err := indexing.NewManager(store, indexing.NewInMemoryRecordManager(),
+		indexing.WithCleanup(indexing.CleanupIncremental), indexing.WithBatchSize(2))
+	require.NoError(t, err)
+
+	_, err = m.Index(ctx, []schema.Document{doc("a1", "a"), doc("a2", "a"), doc("b1", "b")})
+	require.NoError(t, err)
+
+	// a2 changed and source b is not indexed, so b1 is kept
+	result, err := m.Index(ctx, []schema.Document{doc("a1", "a"), doc("a2 changed", "a")})
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 1, NumSkipped: 1, NumDeleted: 1}, result)
+	assert.Equal(t, []string{"a1", "a2 changed", "b1"}, store.contents())
+
+	_, err = m.Index(ctx, []schema.Document{{PageContent: "no source"}})
+	require.ErrorIs(t, err, indexing.ErrNoSourceID)
+}
+
+func TestManagerCleanupFull(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newMemoryStore()
+	m, err := indexing.NewManager(store, indexing.NewInMemoryRecordManager(),
+		indexing.WithCleanup(indexing.CleanupFull))
+	require.NoError(t, err)
+
+	_, err = m.Index(ctx, []schema.Document{doc("a1", "a"), doc("b1", "b")})
+	require.NoError(t, err)
+
+	result, err := m.Index(ctx, []schema.Document{doc("a1", "a")})
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumSkipped: 1, NumDeleted: 1}, result)
+	assert.Equal(t, []string{"a1"}, store.contents())
+	assert.Equal(t, 2, store.added)
+}
+
+func TestManagerIndexLoader(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newMemoryStore()
+	m, err := indexing.NewManager(store, indexing.NewInMemoryRecordManager(),
+		indexing.WithCleanup(indexing.CleanupIncremental), indexing.WithBatchSize(1))
+	require.NoError(t, err)
+
+	loader := documentloaders.NewDirectory("../documentloaders/testdata", documentloaders.WithInclude("*.txt"))
+	result, err := m.IndexLoader(ctx, loader)
+	require.NoError(t, err)
+	assert.Positive(t, result.NumAdded)
+
+	again, err := m.IndexLoader(ctx, loader)
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumSkipped: result.NumAdded}, again)
+}
+
+func TestManagerErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	_, err := indexing.NewManager(newMemoryStore(), indexing.NewInMemoryRecordManager(),
+		indexing.WithCleanup("sometimes"))
+	require.ErrorIs(t, err, indexing.ErrInvalidCleanupMode)
+
+	// a vector store that can not delete documents
+	var store struct{ vectorstores.VectorStore }
+	store.VectorStore = newMemoryStore()
+	m, err := indexing.NewManager(store, indexing.NewInMemoryRecordManager(),
+		indexing.WithCleanup(indexing.CleanupFull))
+	require.NoError(t, err)
+
+	_, err = m.Index(ctx, []schema.Document{doc("a", "a")})
+	require.NoError(t, err)
+	_, err = m.Index(ctx, []schema.Document{doc("b", "b")})
+	require.ErrorIs(t, err, indexing.ErrDeleteNotSupported)
+}
+
+// idlessStore is a vector store that does not return the ids of the documents it
+// adds, or only some of them.
+type idlessStore struct {
+	*memoryStore
+	numIDs int
+}
+
+func (s idlessStore) AddDocuments(ctx context.Context, docs []schema.Document, opts ...vectorstores.Option) ([]string, error) {
+	ids, err := s.memoryStore.AddDocuments(ctx, docs, opts...)
+	return ids[:s.numIDs], err
+}
+
+func TestManagerStoreWithoutIDs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	records := indexing.NewInMemoryRecordManager()
+	store := idlessStore{memoryStore: newMemoryStore()}
+	m, err := indexing.NewManager(store, records, indexing.WithCleanup(indexing.CleanupFull))
+	require.NoError(t, err)
+
+	result, err := m.Index(ctx, []schema.Document{doc("a", "1"), doc("b", "1")})
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumAdded: 2}, result)
+
+	// the stale document can not be deleted without its id, so its record is kept
+	_, err = m.Index(ctx, []schema.Document{doc("a", "1")})
+	require.ErrorIs(t, err, indexing.ErrDeleteNotSupported)
+	assert.Equal(t, []string{"a", "b"}, store.contents())
+
+	incremental, err := indexing.NewManager(store, records, indexing.WithCleanup(indexing.CleanupIncremental))
+	require.NoError(t, err)
+	_, err = incremental.Index(ctx, []schema.Document{doc("a", "1")})
+	require.ErrorIs(t, err, indexing.ErrDeleteNotSupported)
+
+	result, err = m.Index(ctx, []schema.Document{doc("a", "1"), doc("b", "1")})
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumSkipped: 2}, result)
+
+	// the documents are recorded before the wrong number of ids is reported
+	store.numIDs = 1
+	m, err = indexing.NewManager(store, records)
+	require.NoError(t, err)
+	_, err = m.Index(ctx, []schema.Document{doc("c", "1"), doc("d", "1")})
+	require.ErrorIs(t, err, indexing.ErrMismatchedIDs)
+
+	result, err = m.Index(ctx, []schema.Document{doc("c", "1"), doc("d", "1")})
+	require.NoError(t, err)
+	assert.Equal(t, indexing.Result{NumSkipped: 2}, result)
+}
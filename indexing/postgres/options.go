@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultTableName is the default name of the records table.
+const DefaultTableName = "langchaingo_index_records"
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Option is a function type that can be used to modify the record manager.
+type Option func(m *RecordManager)
+
+// WithConnectionURL is an option for specifying the Postgres connection URL. The
+// record manager opens a pgxpool.Pool for it, closed by Close. Either this or WithConn
+// must be used.
+func WithConnectionURL(connectionURL string) Option {
+	return func(m *RecordManager) {
+		m.connURL = connectionURL
+	}
+}
+
+// WithConn is an option for specifying the Postgres connection, such as a
+// pgx.Conn or a pgxpool.Pool. Either this or WithConnectionURL must be used.
+func WithConn(conn PGXConn) Option {
+	return func(m *RecordManager) {
+		m.conn = conn
+	}
+}
+
+// WithTableName is an option for setting the name of the records table.
+func WithTableName(name string) Option {
+	return func(m *RecordManager) {
+		m.tableName = name
+	}
+}
+
+// WithNamespace is an option for setting the namespace of the records, so several
+// vector stores can share a table.
+func WithNamespace(namespace string) Option {
+	return func(m *RecordManager) {
+		m.namespace = namespace
+	}
+}
+
+func applyOptions(opts ...Option) (*RecordManager, error) {
+	m := &RecordManager{
+		tableName: DefaultTableName,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.conn == nil && m.connURL == "" {
+		return nil, fmt.Errorf("%w: missing postgres connection", ErrInvalidOptions)
+	}
+
+	return m, nil
+}
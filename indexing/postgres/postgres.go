@@ -0,0 +1,184 @@
+// Package postgres adds support for persisting indexing records in Postgres.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tmc/langchaingo/indexing"
+)
+
+// PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
+type PGXConn interface {
+	Ping(ctx context.Context) error
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error)
+}
+
+// CloseNoErr is implemented by connections, such as a pgxpool.Pool, that close
+// without an error.
+type CloseNoErr interface {
+	Close()
+}
+
+// RecordManager is an indexing.RecordManager that stores records in a Postgres table.
+// Update times are stored as nanoseconds since the epoch, as Postgres timestamps
+// only have a precision of microseconds.
+type RecordManager struct {
+	conn      PGXConn
+	connURL   string
+	tableName string
+	namespace string
+}
+
+var _ indexing.RecordManager = &RecordManager{}
+
+// New creates a new RecordManager and creates its table if it does not exist.
+func New(ctx context.Context, opts ...Option) (*RecordManager, error) {
+	m, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if m.conn == nil {
+		pool, err := pgxpool.New(ctx, m.connURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := pool.Ping(ctx); err != nil {
+			pool.Close()
+			return nil, err
+		}
+		m.conn = pool
+	} else if err := m.conn.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	schema := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	namespace TEXT NOT NULL,
+	key TEXT NOT NULL,
+	group_id TEXT NOT NULL DEFAULT '',
+	ids TEXT[] NOT NULL,
+	updated_at BIGINT NOT NULL,
+	PRIMARY KEY (namespace, key));
+CREATE INDEX IF NOT EXISTS %[1]s_updated_at ON %[1]s (namespace, updated_at);
+CREATE INDEX IF NOT EXISTS %[1]s_group_id ON %[1]s (namespace, group_id);`, m.tableName)
+	if _, err := m.conn.Exec(ctx, schema); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Close closes the connection, or the pool opened for the connection URL.
+func (m *RecordManager) Close() error {
+	switch conn := m.conn.(type) {
+	case *pgx.Conn:
+		return conn.Close(context.Background())
+	case io.Closer:
+		return conn.Close()
+	case CloseNoErr:
+		conn.Close()
+	}
+	return nil
+}
+
+// DropTable drops the records table.
+func (m *RecordManager) DropTable(ctx context.Context) error {
+	_, err := m.conn.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, m.tableName))
+	return err
+}
+
+// Update creates or replaces the records.
+func (m *RecordManager) Update(ctx context.Context, records []indexing.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	sql := fmt.Sprintf(`INSERT INTO %s (namespace, key, group_id, ids, updated_at)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (namespace, key) DO UPDATE
+		SET group_id = excluded.group_id, ids = excluded.ids, updated_at = excluded.updated_at`, m.tableName)
+	b := &pgx.Batch{}
+	for _, record := range records {
+		ids := record.IDs
+		if ids == nil {
+			ids = []string{}
+		}
+		b.Queue(sql, m.namespace, record.Key, record.GroupID, ids, record.UpdatedAt.UnixNano())
+	}
+	if err := tx.SendBatch(ctx, b).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Get returns the records of the keys that exist.
+func (m *RecordManager) Get(ctx context.Context, keys []string) ([]indexing.Record, error) {
+	if len(keys) == 0 {
+		return []indexing.Record{}, nil
+	}
+	sql := fmt.Sprintf(`SELECT key, group_id, ids, updated_at FROM %s
+		WHERE namespace = $1 AND key = ANY($2)`, m.tableName)
+	return m.query(ctx, sql, m.namespace, keys)
+}
+
+// List returns the records matching the options, ordered by key.
+func (m *RecordManager) List(ctx context.Context, opts indexing.ListOptions) ([]indexing.Record, error) {
+	conditions := []string{"namespace = $1"}
+	args := []any{m.namespace}
+	if !opts.Before.IsZero() {
+		args = append(args, opts.Before.UnixNano())
+		conditions = append(conditions, fmt.Sprintf("updated_at < $%d", len(args)))
+	}
+	if len(opts.GroupIDs) > 0 {
+		args = append(args, opts.GroupIDs)
+		conditions = append(conditions, fmt.Sprintf("group_id = ANY($%d)", len(args)))
+	}
+
+	sql := fmt.Sprintf(`SELECT key, group_id, ids, updated_at FROM %s WHERE %s ORDER BY key`,
+		m.tableName, strings.Join(conditions, " AND "))
+	return m.query(ctx, sql, args...)
+}
+
+// Delete removes the records of the keys.
+func (m *RecordManager) Delete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE namespace = $1 AND key = ANY($2)`, m.tableName)
+	_, err := m.conn.Exec(ctx, sql, m.namespace, keys)
+	return err
+}
+
+func (m *RecordManager) query(ctx context.Context, sql string, args ...any) ([]indexing.Record, error) {
+	rows, err := m.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]indexing.Record, 0)
+	for rows.Next() {
+		var record indexing.Record
+		var updatedAt int64
+		if err := rows.Scan(&record.Key, &record.GroupID, &record.IDs, &updatedAt); err != nil {
+			return nil, err
+		}
+		record.UpdatedAt = time.Unix(0, updatedAt)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/indexing"
+	"github.com/tmc/langchaingo/indexing/postgres"
+)
+
+func preCheckEnvSetting(t *testing.T) string {
+	t.Helper()
+
+	postgresURL := os.Getenv("POSTGRES_CONNECTION_STRING")
+	if postgresURL == "" {
+		container, err := tcpostgres.RunContainer(
+			context.Background(),
+			testcontainers.WithImage("docker.io/postgres:16"),
+			tcpostgres.WithDatabase("db_test"),
+			tcpostgres.WithUsername("user"),
+			tcpostgres.WithPassword("passw0rd!"),
+			testcontainers.WithWaitStrategy(
+				wait.ForLog("database system is ready to accept connections").
+					WithOccurrence(2).
+					WithStartupTimeout(30*time.Second)),
+		)
+		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+			t.Skip("Docker not available")
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, container.Terminate(context.Background()))
+		})
+
+		str, err := container.ConnectionString(context.Background(), "sslmode=disable")
+		require.NoError(t, err)
+
+		postgresURL = str
+	}
+
+	return postgresURL
+}
+
+func TestRecordManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m, err := postgres.New(ctx,
+		postgres.WithConnectionURL(preCheckEnvSetting(t)),
+		postgres.WithTableName("test_index_records"),
+		postgres.WithNamespace("docs"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, m.DropTable(ctx))
+		require.NoError(t, m.Close())
+	})
+
+	before := time.Now()
+	after := before.Add(time.Second)
+	require.NoError(t, m.Update(ctx, []indexing.Record{
+		{Key: "a", GroupID: "1", IDs: []string{"id-a"}, UpdatedAt: before},
+		{Key: "b", GroupID: "2", IDs: []string{"id-b"}, UpdatedAt: after},
+	}))
+
+	records, err := m.Get(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	records, err = m.List(ctx, indexing.ListOptions{Before: after, GroupIDs: []string{"1", "2"}})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "a", records[0].Key)
+	require.Equal(t, []string{"id-a"}, records[0].IDs)
+	require.True(t, before.Equal(records[0].UpdatedAt))
+
+	require.NoError(t, m.Delete(ctx, []string{"a", "b"}))
+	records, err = m.List(ctx, indexing.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,119 @@
+package indexing
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record is the record of a document added to a vector store.
+type Record struct {
+	// Key is the hash of the content and the metadata of the document.
+	Key string
+	// GroupID is the id of the source of the document, if any.
+	GroupID string
+	// IDs are the ids the vector store returned for the document. They are empty
+	// for vector stores that do not return ids, such as milvus and weaviate.
+	IDs []string
+	// UpdatedAt is the time the document was last indexed.
+	UpdatedAt time.Time
+}
+
+// ListOptions limits the records returned by RecordManager.List.
+type ListOptions struct {
+	// Before only lists records updated before the time, if it is not zero.
+	Before time.Time
+	// GroupIDs only lists records of the groups, if it is not empty.
+	GroupIDs []string
+}
+
+// RecordManager is the interface for keeping track of the documents that have been
+// added to a vector store.
+type RecordManager interface {
+	// Update creates or replaces the records.
+	Update(ctx context.Context, records []Record) error
+	// Get returns the records of the keys that exist.
+	Get(ctx context.Context, keys []string) ([]Record, error)
+	// List returns the records matching the options.
+	List(ctx context.Context, opts ListOptions) ([]Record, error)
+	// Delete removes the records of the keys.
+	Delete(ctx context.Context, keys []string) error
+}
+
+// InMemoryRecordManager is a RecordManager that keeps records in memory.
+// It is safe for concurrent use.
+type InMemoryRecordManager struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+var _ RecordManager = &InMemoryRecordManager{}
+
+// NewInMemoryRecordManager creates a new in-memory record manager.
+func NewInMemoryRecordManager() *InMemoryRecordManager {
+	return &InMemoryRecordManager{
+		records: make(map[string]Record),
+	}
+}
+
+// Update creates or replaces the records.
+func (m *InMemoryRecordManager) Update(_ context.Context, records []Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, record := range records {
+		record.IDs = append([]string(nil), record.IDs...)
+		m.records[record.Key] = record
+	}
+	return nil
+}
+
+// Get returns the records of the keys that exist.
+func (m *InMemoryRecordManager) Get(_ context.Context, keys []string) ([]Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records := make([]Record, 0, len(keys))
+	for _, key := range keys {
+		if record, ok := m.records[key]; ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// List returns the records matching the options, ordered by key.
+func (m *InMemoryRecordManager) List(_ context.Context, opts ListOptions) ([]Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	groups := make(map[string]bool, len(opts.GroupIDs))
+	for _, id := range opts.GroupIDs {
+		groups[id] = true
+	}
+
+	records := make([]Record, 0)
+	for _, record := range m.records {
+		if !opts.Before.IsZero() && !record.UpdatedAt.Before(opts.Before) {
+			continue
+		}
+		if len(groups) > 0 && !groups[record.GroupID] {
+			continue
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+	return records, nil
+}
+
+// Delete removes the records of the keys.
+func (m *InMemoryRecordManager) Delete(_ context.Context, keys []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.records, key)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package sqlite3 adds support for
+// persisting indexing records using sqlite3.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/indexing"
+	"github.com/tmc/langchaingo/internal/sqlitedb"
+)
+
+// maxVariables is the number of keys put in one query, below the limit of
+// variables of sqlite.
+const maxVariables = 500
+
+// RecordManager is an indexing.RecordManager that stores records in a sqlite3 table.
+type RecordManager struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the records table.
+	TableName string
+	// Namespace is the namespace of the records.
+	Namespace string
+}
+
+// Statically assert that RecordManager implement the record manager interface.
+var _ indexing.RecordManager = &RecordManager{}
+
+// New creates a new RecordManager and creates its table if it does not exist.
+func New(ctx context.Context, options ...Option) (*RecordManager, error) {
+	m := applyOptions(options...)
+
+	if m.DB == nil {
+		db, err := sqlitedb.Open(m.DBAddress)
+		if err != nil {
+			return nil, err
+		}
+		m.DB = db
+	}
+
+	if _, err := m.DB.ExecContext(ctx, fmt.Sprintf(DefaultSchema, m.TableName)); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Update creates or replaces the records.
+func (m *RecordManager) Update(ctx context.Context, records []indexing.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	querytpl := []string{
+		"INSERT INTO ",
+		" (namespace, key, group_id, ids, updated_at) VALUES (?, ?, ?, ?, ?)" +
+			" ON CONFLICT(namespace, key) DO UPDATE SET group_id = excluded.group_id," +
+			" ids = excluded.ids, updated_at = excluded.updated_at;",
+	}
+	stmt, err := tx.PrepareContext(ctx, strings.Join(querytpl, m.TableName))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		ids, err := json.Marshal(record.IDs)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.ExecContext(ctx, m.Namespace, record.Key, record.GroupID, string(ids), record.UpdatedAt.UnixNano())
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get returns the records of the keys that exist.
+func (m *RecordManager) Get(ctx context.Context, keys []string) ([]indexing.Record, error) {
+	records := make([]indexing.Record, 0, len(keys))
+	for start := 0; start < len(keys); start += maxVariables {
+		batch := keys[start:min(start+maxVariables, len(keys))]
+		query := fmt.Sprintf("SELECT key, group_id, ids, updated_at FROM %s WHERE namespace = ? AND key IN (%s);",
+			m.TableName, placeholders(len(batch)))
+		found, err := m.query(ctx, query, append([]any{m.Namespace}, toArgs(batch)...)...)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, found...)
+	}
+	return records, nil
+}
+
+// List returns the records matching the options, ordered by key.
+func (m *RecordManager) List(ctx context.Context, opts indexing.ListOptions) ([]indexing.Record, error) {
+	conditions := []string{"namespace = ?"}
+	args := []any{m.Namespace}
+	if !opts.Before.IsZero() {
+		conditions = append(conditions, "updated_at < ?")
+		args = append(args, opts.Before.UnixNano())
+	}
+	if len(opts.GroupIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("group_id IN (%s)", placeholders(len(opts.GroupIDs))))
+		args = append(args, toArgs(opts.GroupIDs)...)
+	}
+
+	query := fmt.Sprintf("SELECT key, group_id, ids, updated_at FROM %s WHERE %s ORDER BY key;",
+		m.TableName, strings.Join(conditions, " AND "))
+	return m.query(ctx, query, args...)
+}
+
+// Delete removes the records of the keys.
+func (m *RecordManager) Delete(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += maxVariables {
+		batch := keys[start:min(start+maxVariables, len(keys))]
+		query := fmt.Sprintf("DELETE FROM %s WHERE namespace = ? AND key IN (%s);",
+			m.TableName, placeholders(len(batch)))
+		if _, err := m.DB.ExecContext(ctx, query, append([]any{m.Namespace}, toArgs(batch)...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *RecordManager) query(ctx context.Context, query string, args ...any) ([]indexing.Record, error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]indexing.Record, 0)
+	for rows.Next() {
+		var record indexing.Record
+		var ids string
+		var updatedAt int64
+		if err := rows.Scan(&record.Key, &record.GroupID, &ids, &updatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(ids), &record.IDs); err != nil {
+			return nil, err
+		}
+		record.UpdatedAt = time.Unix(0, updatedAt)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
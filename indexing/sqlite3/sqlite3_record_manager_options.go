@@ -0,0 +1,73 @@
+package sqlite3
+
+import (
+	"database/sql"
+)
+
+// DefaultTableName sets a default table name.
+const DefaultTableName = "langchaingo_index_records"
+
+// DefaultSchema sets a default schema to be run after connecting.
+const DefaultSchema = `CREATE TABLE IF NOT EXISTS %[1]s (
+		namespace TEXT NOT NULL,
+		key TEXT NOT NULL,
+		group_id TEXT NOT NULL DEFAULT '',
+		ids TEXT NOT NULL,
+		updated_at INTEGER NOT NULL,
+		PRIMARY KEY (namespace, key)
+);
+CREATE INDEX IF NOT EXISTS idx_%[1]s_updated_at ON %[1]s (namespace, updated_at);
+CREATE INDEX IF NOT EXISTS idx_%[1]s_group_id ON %[1]s (namespace, group_id);`
+
+// Option is a function for creating a new record manager
+// with other than the default values.
+type Option func(m *RecordManager)
+
+// WithDB is an option for New for adding a database connection.
+func WithDB(db *sql.DB) Option {
+	return func(m *RecordManager) {
+		m.DB = db
+	}
+}
+
+// WithDBAddress is an option for New for specifying an address
+// or file path for when connecting the db.
+func WithDBAddress(addr string) Option {
+	return func(m *RecordManager) {
+		m.DBAddress = addr
+	}
+}
+
+// WithTableName is an option for New for setting the name of
+// the records table.
+func WithTableName(name string) Option {
+	return func(m *RecordManager) {
+		m.TableName = name
+	}
+}
+
+// WithNamespace is an option for New for setting the namespace of
+// the records, so several vector stores can share a table.
+func WithNamespace(namespace string) Option {
+	return func(m *RecordManager) {
+		m.Namespace = namespace
+	}
+}
+
+func applyOptions(options ...Option) *RecordManager {
+	m := &RecordManager{}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.TableName == "" {
+		m.TableName = DefaultTableName
+	}
+
+	if m.DBAddress == "" {
+		m.DBAddress = ":memory:"
+	}
+
+	return m
+}
@@ -0,0 +1,50 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/indexing"
+	"github.com/tmc/langchaingo/indexing/sqlite3"
+)
+
+func TestRecordManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m, err := sqlite3.New(ctx, sqlite3.WithNamespace("docs"))
+	require.NoError(t, err)
+
+	before := time.Now()
+	after := before.Add(time.Second)
+	require.NoError(t, m.Update(ctx, []indexing.Record{
+		{Key: "a", GroupID: "1", IDs: []string{"id-a"}, UpdatedAt: before},
+		{Key: "b", GroupID: "2", IDs: []string{"id-b"}, UpdatedAt: before},
+	}))
+	require.NoError(t, m.Update(ctx, []indexing.Record{
+		{Key: "b", GroupID: "2", IDs: []string{"id-b"}, UpdatedAt: after},
+	}))
+
+	records, err := m.Get(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	records, err = m.List(ctx, indexing.ListOptions{Before: after})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "a", records[0].Key)
+	require.Equal(t, []string{"id-a"}, records[0].IDs)
+	require.True(t, before.Equal(records[0].UpdatedAt))
+
+	records, err = m.List(ctx, indexing.ListOptions{GroupIDs: []string{"2"}})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "b", records[0].Key)
+
+	require.NoError(t, m.Delete(ctx, []string{"a"}))
+	records, err = m.List(ctx, indexing.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+}
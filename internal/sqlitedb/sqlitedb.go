@@ -0,0 +1,25 @@
+// Package sqlitedb contains helpers shared by the sqlite3 backed stores.
+package sqlitedb
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+)
+
+// MemoryAddress is the address of an in-memory database.
+const MemoryAddress = ":memory:"
+
+// Open opens the sqlite3 database at the address. Every connection to an
+// in-memory database opens a new, empty database, so an in-memory database is
+// kept on a single connection.
+func Open(address string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", address)
+	if err != nil {
+		return nil, err
+	}
+	if address == MemoryAddress {
+		db.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
@@ -167,22 +167,51 @@ func getMessageRole(m ChatMessage, humanPrefix, aiPrefix string) (string, error)
 	return role, nil
 }
 
+// ChatMessageModelData is the data of a chat message in a ChatMessageModel. The
+// fields other than the content and the type are only set for the message types
+// that have them.
 type ChatMessageModelData struct {
 	Content string `bson:"content" json:"content"`
 	Type    string `bson:"type"    json:"type"`
+	// Name is the name of a generic or a function message.
+	Name string `bson:"name,omitempty" json:"name,omitempty"`
+	// Role is the role of a generic message.
+	Role string `bson:"role,omitempty" json:"role,omitempty"`
+	// ToolCallID is the id of the tool call a tool message responds to.
+	ToolCallID string `bson:"tool_call_id,omitempty" json:"tool_call_id,omitempty"`
+	// FunctionCall is the function call of an AI message.
+	FunctionCall *FunctionCall `bson:"function_call,omitempty" json:"function_call,omitempty"`
+	// ToolCalls are the tool calls of an AI message.
+	ToolCalls []ToolCall `bson:"tool_calls,omitempty" json:"tool_calls,omitempty"`
 }
 
+// ChatMessageModel is a chat message in a form that can be stored and converted
+// back to the same chat message.
 type ChatMessageModel struct {
 	Type string               `bson:"type" json:"type"`
 	Data ChatMessageModelData `bson:"data" json:"data"`
 }
 
+// ToChatMessage converts the model back to a chat message. It returns nil for
+// unknown message types.
 func (c ChatMessageModel) ToChatMessage() ChatMessage {
 	switch c.Type {
 	case string(ChatMessageTypeAI):
-		return AIChatMessage{Content: c.Data.Content}
+		return AIChatMessage{
+			Content:      c.Data.Content,
+			FunctionCall: c.Data.FunctionCall,
+			ToolCalls:    c.Data.ToolCalls,
+		}
 	case string(ChatMessageTypeHuman):
 		return HumanChatMessage{Content: c.Data.Content}
+	case string(ChatMessageTypeSystem):
+		return SystemChatMessage{Content: c.Data.Content}
+	case string(ChatMessageTypeGeneric):
+		return GenericChatMessage{Content: c.Data.Content, Role: c.Data.Role, Name: c.Data.Name}
+	case string(ChatMessageTypeFunction):
+		return FunctionChatMessage{Name: c.Data.Name, Content: c.Data.Content}
+	case string(ChatMessageTypeTool):
+		return ToolChatMessage{ID: c.Data.ToolCallID, Content: c.Data.Content}
 	default:
 		slog.Warn("convert to chat message failed with invalid message type", "type", c.Type)
 		return nil
@@ -191,11 +220,24 @@ func (c ChatMessageModel) ToChatMessage() ChatMessage {
 
 // ConvertChatMessageToModel Convert a ChatMessage to a ChatMessageModel.
 func ConvertChatMessageToModel(m ChatMessage) ChatMessageModel {
+	data := ChatMessageModelData{
+		Type:    string(m.GetType()),
+		Content: m.GetContent(),
+	}
+	switch m := m.(type) {
+	case AIChatMessage:
+		data.FunctionCall = m.FunctionCall
+		data.ToolCalls = m.ToolCalls
+	case GenericChatMessage:
+		data.Role = m.Role
+		data.Name = m.Name
+	case FunctionChatMessage:
+		data.Name = m.Name
+	case ToolChatMessage:
+		data.ToolCallID = m.ID
+	}
 	return ChatMessageModel{
 		Type: string(m.GetType()),
-		Data: ChatMessageModelData{
-			Type:    string(m.GetType()),
-			Content: m.GetContent(),
-		},
+		Data: data,
 	}
 }
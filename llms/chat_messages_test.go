@@ -1,8 +1,10 @@
 package llms_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/llms"
 )
 
@@ -68,3 +70,43 @@ type unsupportedChatMessage struct{}
 
 func (m unsupportedChatMessage) GetType() llms.ChatMessageType { return "unsupported" }
 func (m unsupportedChatMessage) GetContent() string            { return "Unsupported message" }
+
+func TestChatMessageModelRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	messages := []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "Please be polite."},
+		llms.HumanChatMessage{Content: "What is the weather in Berlin?"},
+		llms.AIChatMessage{
+			Content: "Let me check.",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "get_weather", Arguments: `{"location":"Berlin"}`},
+			}},
+		},
+		llms.AIChatMessage{FunctionCall: &llms.FunctionCall{Name: "get_weather", Arguments: "{}"}},
+		llms.ToolChatMessage{ID: "call_1", Content: "sunny"},
+		llms.FunctionChatMessage{Name: "get_weather", Content: "sunny"},
+		llms.GenericChatMessage{Role: "Moderator", Name: "bob", Content: "Keep the conversation on topic."},
+	}
+
+	for _, message := range messages {
+		data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+		require.NoError(t, err)
+
+		var model llms.ChatMessageModel
+		require.NoError(t, json.Unmarshal(data, &model))
+		require.Equal(t, message, model.ToChatMessage())
+	}
+}
+
+func TestChatMessageModelWithoutData(t *testing.T) {
+	t.Parallel()
+
+	// models stored before the other fields existed only have a content and a type.
+	var model llms.ChatMessageModel
+	err := json.Unmarshal([]byte(`{"type":"ai","data":{"content":"foo","type":"ai"}}`), &model)
+	require.NoError(t, err)
+	require.Equal(t, llms.AIChatMessage{Content: "foo"}, model.ToChatMessage())
+}
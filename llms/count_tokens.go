@@ -1,9 +1,9 @@
 package llms
 
 import (
-	"log"
-
-	"github.com/pkoukk/tiktoken-go"
+	"regexp"
+	"strings"
+	"sync"
 )
 
 const (
@@ -11,54 +11,184 @@ const (
 )
 
 const (
-	_gpt35TurboContextSize   = 4096
-	_gpt432KContextSize      = 32768
-	_gpt4ContextSize         = 8192
-	_textDavinci3ContextSize = 4097
-	_textBabbage1ContextSize = 2048
-	_textAda1ContextSize     = 2048
-	_textCurie1ContextSize   = 2048
-	_codeDavinci2ContextSize = 8000
-	_codeCushman1ContextSize = 2048
-	_textBisonContextSize    = 2048
-	_chatBisonContextSize    = 2048
-	_defaultContextSize      = 2048
+	_defaultContextSize = 2048
 )
 
+// ModelSize is the number of tokens a model can handle.
+type ModelSize struct {
+	// ContextSize is the number of tokens of the context window, shared by the
+	// input and the output.
+	ContextSize int
+	// MaxOutputTokens is the number of tokens the model can generate in one
+	// response, or 0 if it is only limited by the context window.
+	MaxOutputTokens int
+}
+
+// modelSizes are the sizes of the models by model id. A model id that is not
+// listed uses the size of the longest model id that it starts with, so dated
+// versions such as "gpt-4o-2024-08-06" are covered by "gpt-4o".
+//
 // nolint:gochecknoglobals
-var modelToContextSize = map[string]int{
-	"gpt-3.5-turbo":    _gpt35TurboContextSize,
-	"gpt-4-32k":        _gpt432KContextSize,
-	"gpt-4":            _gpt4ContextSize,
-	"text-davinci-003": _textDavinci3ContextSize,
-	"text-curie-001":   _textCurie1ContextSize,
-	"text-babbage-001": _textBabbage1ContextSize,
-	"text-ada-001":     _textAda1ContextSize,
-	"code-davinci-002": _codeDavinci2ContextSize,
-	"code-cushman-001": _codeCushman1ContextSize,
+var modelSizes = struct {
+	sync.RWMutex
+	sizes map[string]ModelSize
+}{sizes: map[string]ModelSize{
+	// OpenAI
+	"gpt-3.5-turbo":          {ContextSize: 16385, MaxOutputTokens: 4096},
+	"gpt-3.5-turbo-0613":     {ContextSize: 4096, MaxOutputTokens: 4096},
+	"gpt-3.5-turbo-16k":      {ContextSize: 16385, MaxOutputTokens: 4096},
+	"gpt-3.5-turbo-instruct": {ContextSize: 4096, MaxOutputTokens: 4096},
+	"gpt-4":                  {ContextSize: 8192, MaxOutputTokens: 8192},
+	"gpt-4-32k":              {ContextSize: 32768, MaxOutputTokens: 32768},
+	"gpt-4-turbo":            {ContextSize: 128000, MaxOutputTokens: 4096},
+	"gpt-4-1106-preview":     {ContextSize: 128000, MaxOutputTokens: 4096},
+	"gpt-4-0125-preview":     {ContextSize: 128000, MaxOutputTokens: 4096},
+	"gpt-4-vision-preview":   {ContextSize: 128000, MaxOutputTokens: 4096},
+	"gpt-4o":                 {ContextSize: 128000, MaxOutputTokens: 16384},
+	"gpt-4o-2024-05-13":      {ContextSize: 128000, MaxOutputTokens: 4096},
+	"gpt-4o-mini":            {ContextSize: 128000, MaxOutputTokens: 16384},
+	"gpt-4.1":                {ContextSize: 1047576, MaxOutputTokens: 32768},
+	"o1":                     {ContextSize: 200000, MaxOutputTokens: 100000},
+	"o1-preview":             {ContextSize: 128000, MaxOutputTokens: 32768},
+	"o1-mini":                {ContextSize: 128000, MaxOutputTokens: 65536},
+	"o3":                     {ContextSize: 200000, MaxOutputTokens: 100000},
+	"o4-mini":                {ContextSize: 200000, MaxOutputTokens: 100000},
+	"text-davinci-003":       {ContextSize: 4097},
+	"text-curie-001":         {ContextSize: 2048},
+	"text-babbage-001":       {ContextSize: 2048},
+	"text-ada-001":           {ContextSize: 2048},
+	"code-davinci-002":       {ContextSize: 8000},
+	"code-cushman-001":       {ContextSize: 2048},
+
+	// Anthropic
+	"claude-instant-1": {ContextSize: 100000, MaxOutputTokens: 4096},
+	"claude-2.0":       {ContextSize: 100000, MaxOutputTokens: 4096},
+	"claude-2.1":       {ContextSize: 200000, MaxOutputTokens: 4096},
+	"claude-3":         {ContextSize: 200000, MaxOutputTokens: 4096},
+	"claude-3-5":       {ContextSize: 200000, MaxOutputTokens: 8192},
+	"claude-3-7":       {ContextSize: 200000, MaxOutputTokens: 64000},
+	"claude-sonnet-4":  {ContextSize: 200000, MaxOutputTokens: 64000},
+	"claude-opus-4":    {ContextSize: 200000, MaxOutputTokens: 32000},
+
+	// Google
+	"gemini-pro":       {ContextSize: 32760, MaxOutputTokens: 8192},
+	"gemini-1.0-pro":   {ContextSize: 32760, MaxOutputTokens: 8192},
+	"gemini-1.5-pro":   {ContextSize: 2097152, MaxOutputTokens: 8192},
+	"gemini-1.5-flash": {ContextSize: 1048576, MaxOutputTokens: 8192},
+	"gemini-2.0-flash": {ContextSize: 1048576, MaxOutputTokens: 8192},
+	"gemini-2.5":       {ContextSize: 1048576, MaxOutputTokens: 65536},
+	"text-bison":       {ContextSize: 8192, MaxOutputTokens: 1024},
+	"chat-bison":       {ContextSize: 8192, MaxOutputTokens: 1024},
+
+	// Meta
+	"llama2":    {ContextSize: 4096},
+	"llama-2":   {ContextSize: 4096},
+	"llama3":    {ContextSize: 8192},
+	"llama-3":   {ContextSize: 8192},
+	"llama3.1":  {ContextSize: 131072},
+	"llama-3.1": {ContextSize: 131072},
+	"llama3.2":  {ContextSize: 131072},
+	"llama-3.2": {ContextSize: 131072},
+	"llama3.3":  {ContextSize: 131072},
+	"llama-3.3": {ContextSize: 131072},
+
+	// Mistral
+	"mistral":              {ContextSize: 32768},
+	"mistral-large":        {ContextSize: 131072},
+	"mistral-small":        {ContextSize: 32768},
+	"open-mistral-7b":      {ContextSize: 32768},
+	"open-mistral-nemo":    {ContextSize: 131072},
+	"mixtral":              {ContextSize: 32768},
+	"open-mixtral-8x7b":    {ContextSize: 32768},
+	"open-mixtral-8x22b":   {ContextSize: 65536},
+	"mistral-nemo":         {ContextSize: 131072},
+	"codestral":            {ContextSize: 32768},
+	"open-codestral-mamba": {ContextSize: 262144},
+
+	// Cohere
+	"command":        {ContextSize: 4096, MaxOutputTokens: 4000},
+	"command-r":      {ContextSize: 128000, MaxOutputTokens: 4000},
+	"command-r-plus": {ContextSize: 128000, MaxOutputTokens: 4000},
+}}
+
+// vendorPrefix matches the vendor prefix of the model ids of platforms such as
+// Amazon Bedrock, as in "anthropic.claude-3-haiku-20240307-v1:0".
+var vendorPrefix = regexp.MustCompile(`^[a-z]+\.`)
+
+// RegisterModelSize adds or replaces the size of a model id. It also applies to
+// the model ids starting with it that have no size of their own.
+func RegisterModelSize(model string, size ModelSize) {
+	modelSizes.Lock()
+	defer modelSizes.Unlock()
+
+	modelSizes.sizes[model] = size
+}
+
+// GetModelSize returns the size of a model, and whether it is known. Besides the
+// model id itself, the id after its last "/" and without vendor prefixes such as
+// "anthropic." are looked up, so "models/gemini-1.5-pro" and Bedrock model ids
+// are found.
+func GetModelSize(model string) (ModelSize, bool) {
+	modelSizes.RLock()
+	defer modelSizes.RUnlock()
+
+	candidates := []string{model}
+	name := model[strings.LastIndex(model, "/")+1:]
+	for {
+		candidates = append(candidates, name)
+		prefix := vendorPrefix.FindString(name)
+		if prefix == "" {
+			break
+		}
+		name = name[len(prefix):]
+	}
+
+	for _, candidate := range candidates {
+		if size, ok := modelSizes.sizes[candidate]; ok {
+			return size, true
+		}
+		found := ""
+		for id := range modelSizes.sizes {
+			if strings.HasPrefix(candidate, id) && len(id) > len(found) {
+				found = id
+			}
+		}
+		if found != "" {
+			return modelSizes.sizes[found], true
+		}
+	}
+	return ModelSize{}, false
 }
 
 // GetModelContextSize gets the max number of tokens for a language model. If the model
 // name isn't recognized the default value 2048 is returned.
 func GetModelContextSize(model string) int {
-	contextSize, ok := modelToContextSize[model]
+	size, ok := GetModelSize(model)
 	if !ok {
 		return _defaultContextSize
 	}
-	return contextSize
+	return size.ContextSize
 }
 
-// CountTokens gets the number of tokens the text contains.
-func CountTokens(model, text string) int {
-	e, err := tiktoken.EncodingForModel(model)
-	if err != nil {
-		e, err = tiktoken.GetEncoding("gpt2")
-		if err != nil {
-			log.Printf("[WARN] Failed to calculate number of tokens for model, falling back to approximate count")
-			return len([]rune(text)) / _tokenApproximation
-		}
+// GetModelMaxOutputTokens gets the max number of tokens a language model can
+// generate in one response. It is the context size for models whose output is only
+// limited by their context window, and the default value 2048 if the model name
+// isn't recognized.
+func GetModelMaxOutputTokens(model string) int {
+	size, ok := GetModelSize(model)
+	if !ok {
+		return _defaultContextSize
+	}
+	if size.MaxOutputTokens == 0 {
+		return size.ContextSize
 	}
-	return len(e.Encode(text, nil, nil))
+	return size.MaxOutputTokens
+}
+
+// CountTokens gets the number of tokens the text contains, with the tokenizer of
+// the model returned by GetTokenizer.
+func CountTokens(model, text string) int {
+	return GetTokenizer(model).CountTokens(text)
 }
 
 // CalculateMaxTokens calculates the max number of tokens that could be added to a text.
@@ -12,3 +12,44 @@ func TestCountTokens(t *testing.T) {
 	expectedNumTokens := 4
 	assert.Equal(t, expectedNumTokens, numTokens)
 }
+
+func TestGetModelSize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		model           string
+		contextSize     int
+		maxOutputTokens int
+	}{
+		{"gpt-4", 8192, 8192},
+		{"gpt-4-32k", 32768, 32768},
+		{"gpt-4o", 128000, 16384},
+		{"gpt-4o-2024-08-06", 128000, 16384},
+		{"gpt-4o-2024-05-13", 128000, 4096},
+		{"gpt-4o-mini-2024-07-18", 128000, 16384},
+		{"claude-3-5-sonnet-20241022", 200000, 8192},
+		{"claude-3-haiku-20240307", 200000, 4096},
+		{"anthropic.claude-3-haiku-20240307-v1:0", 200000, 4096},
+		{"us.anthropic.claude-3-7-sonnet-20250219-v1:0", 200000, 64000},
+		{"models/gemini-1.5-pro", 2097152, 8192},
+		{"llama3.1:8b", 131072, 131072},
+		{"mistral-large-latest", 131072, 131072},
+		{"unknown-model", 2048, 2048},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.contextSize, GetModelContextSize(c.model), c.model)
+		assert.Equal(t, c.maxOutputTokens, GetModelMaxOutputTokens(c.model), c.model)
+	}
+
+	_, ok := GetModelSize("unknown-model")
+	assert.False(t, ok)
+}
+
+func TestRegisterModelSize(t *testing.T) {
+	t.Parallel()
+
+	RegisterModelSize("test-size-model", ModelSize{ContextSize: 1000, MaxOutputTokens: 100})
+	size, ok := GetModelSize("test-size-model-v2")
+	assert.True(t, ok)
+	assert.Equal(t, ModelSize{ContextSize: 1000, MaxOutputTokens: 100}, size)
+}
@@ -16,4 +16,11 @@
 // The `llms.go` file contains the types and interfaces for interacting with different LLMs.
 //
 // The `options.go` file provides various options and functions to configure the LLMs.
+//
+// The `trim.go` file provides TrimMessages, which fits a conversation into a token budget.
+//
+// The `tokenizer.go` file provides the tokenizer registry: providers register how the
+// tokens of their models are counted with RegisterTokenizer, and GetTokenizer returns
+// the tokenizer of a model. The `count_tokens.go` file keeps the context window and
+// maximum output size of models, returned by GetModelSize.
 package llms
@@ -243,16 +243,20 @@ func (tc *ToolCall) UnmarshalJSON(data []byte) error {
 	if !ok {
 		return fmt.Errorf("invalid type field in ToolCall")
 	}
-	var fc FunctionCall
-	fcData, ok := toolCall["function"].(json.RawMessage)
-	if ok {
+	tc.ID = id
+	tc.Type = typ
+	tc.FunctionCall = nil
+	if function, ok := toolCall["function"].(map[string]any); ok {
+		fcData, err := json.Marshal(function)
+		if err != nil {
+			return fmt.Errorf("error marshalling function call: %w", err)
+		}
+		var fc FunctionCall
 		if err := json.Unmarshal(fcData, &fc); err != nil {
 			return fmt.Errorf("error unmarshalling function call: %w", err)
 		}
+		tc.FunctionCall = &fc
 	}
-	tc.ID = id
-	tc.Type = typ
-	tc.FunctionCall = &fc
 	return nil
 }
 
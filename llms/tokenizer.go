@@ -0,0 +1,142 @@
+package llms
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Tokenizer counts the tokens of texts for a model.
+type Tokenizer interface {
+	// CountTokens returns the number of tokens of the text.
+	CountTokens(text string) int
+}
+
+// TokenEncoder is a Tokenizer that can also turn texts into tokens and back, as
+// needed to split texts by tokens.
+type TokenEncoder interface {
+	Tokenizer
+	// Encode returns the tokens of the text.
+	Encode(text string) []int
+	// Decode returns the text of the tokens.
+	Decode(tokens []int) string
+}
+
+// TokenizerFactory returns the tokenizer of a model.
+type TokenizerFactory func(model string) (Tokenizer, error)
+
+// Statically assert that the tokenizers implement the interfaces.
+var (
+	_ TokenEncoder = &TiktokenTokenizer{}
+	_ Tokenizer    = ApproximateTokenizer{}
+)
+
+// TiktokenTokenizer is a TokenEncoder using a tiktoken encoding, as used by the
+// OpenAI models.
+type TiktokenTokenizer struct {
+	Encoding *tiktoken.Tiktoken
+	// AllowedSpecial are the special tokens encoded as such.
+	AllowedSpecial []string
+	// DisallowedSpecial are the special tokens not allowed in texts, or "all".
+	DisallowedSpecial []string
+}
+
+// NewTiktokenTokenizer returns the tokenizer of a tiktoken encoding, such as
+// "cl100k_base".
+func NewTiktokenTokenizer(encoding string) (*TiktokenTokenizer, error) {
+	e, err := tiktoken.GetEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &TiktokenTokenizer{Encoding: e}, nil
+}
+
+// CountTokens returns the number of tokens of the text.
+func (t *TiktokenTokenizer) CountTokens(text string) int {
+	return len(t.Encode(text))
+}
+
+// Encode returns the tokens of the text.
+func (t *TiktokenTokenizer) Encode(text string) []int {
+	return t.Encoding.Encode(text, t.AllowedSpecial, t.DisallowedSpecial)
+}
+
+// Decode returns the text of the tokens.
+func (t *TiktokenTokenizer) Decode(tokens []int) string {
+	return t.Encoding.Decode(tokens)
+}
+
+// ApproximateTokenizer approximates the number of tokens of a text as a quarter of
+// the number of its characters. It is used when no tokenizer can be loaded.
+type ApproximateTokenizer struct{}
+
+// CountTokens returns the approximate number of tokens of the text.
+func (ApproximateTokenizer) CountTokens(text string) int {
+	return len([]rune(text)) / _tokenApproximation
+}
+
+// tiktokenFactory returns the tiktoken encoding of the model, or else the gpt2
+// encoding.
+func tiktokenFactory(model string) (Tokenizer, error) {
+	e, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		e, err = tiktoken.GetEncoding("gpt2")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &TiktokenTokenizer{Encoding: e}, nil
+}
+
+// nolint:gochecknoglobals
+var tokenizers = struct {
+	sync.RWMutex
+	factories map[string]TokenizerFactory
+	cache     map[string]Tokenizer
+}{
+	factories: map[string]TokenizerFactory{},
+	cache:     map[string]Tokenizer{},
+}
+
+// RegisterTokenizer registers the factory of the tokenizers of the models whose
+// ids start with the prefix, such as "claude-". The factory of the longest prefix
+// matching a model id is used. Models that match no prefix use tiktoken.
+func RegisterTokenizer(prefix string, factory TokenizerFactory) {
+	tokenizers.Lock()
+	defer tokenizers.Unlock()
+
+	tokenizers.factories[prefix] = factory
+	tokenizers.cache = map[string]Tokenizer{}
+}
+
+// GetTokenizer returns the tokenizer of a model, created by the factory registered
+// for the model id, or else by tiktoken. If the tokenizer cannot be created, the
+// ApproximateTokenizer is returned. Tokenizers are cached per model.
+func GetTokenizer(model string) Tokenizer {
+	tokenizers.RLock()
+	tokenizer, ok := tokenizers.cache[model]
+	factory, prefix := tiktokenFactory, ""
+	for p, f := range tokenizers.factories {
+		if strings.HasPrefix(model, p) && len(p) >= len(prefix) {
+			factory, prefix = f, p
+		}
+	}
+	tokenizers.RUnlock()
+	if ok {
+		return tokenizer
+	}
+
+	tokenizer, err := factory(model)
+	if err != nil {
+		log.Printf("[WARN] Failed to calculate number of tokens for model, falling back to approximate count")
+		// the approximation is not cached, so the tokenizer is loaded again next time.
+		return ApproximateTokenizer{}
+	}
+
+	tokenizers.Lock()
+	tokenizers.cache[model] = tokenizer
+	tokenizers.Unlock()
+	return tokenizer
+}
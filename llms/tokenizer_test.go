@@ -0,0 +1,40 @@
+package llms
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type wordTokenizer struct{}
+
+func (wordTokenizer) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestRegisterTokenizer(t *testing.T) {
+	t.Parallel()
+
+	var models []string
+	RegisterTokenizer("test-tokenizer-", func(model string) (Tokenizer, error) {
+		models = append(models, model)
+		return wordTokenizer{}, nil
+	})
+	RegisterTokenizer("test-tokenizer-broken-", func(string) (Tokenizer, error) {
+		return nil, errors.New("no tokenizer")
+	})
+
+	assert.Equal(t, 4, CountTokens("test-tokenizer-a", "test for counting tokens"))
+	assert.Equal(t, 4, CountTokens("test-tokenizer-a", "test for counting tokens"))
+	// tokenizers are cached per model.
+	assert.Equal(t, []string{"test-tokenizer-a"}, models)
+
+	// the longest prefix is used, and the approximation if it fails.
+	assert.Equal(t, ApproximateTokenizer{}, GetTokenizer("test-tokenizer-broken-a"))
+	assert.Equal(t, 6, CountTokens("test-tokenizer-broken-a", "test for counting tokens"))
+
+	RegisterModelSize("test-tokenizer-a", ModelSize{ContextSize: 100})
+	assert.Equal(t, 96, CalculateMaxTokens("test-tokenizer-a", "test for counting tokens"))
+}
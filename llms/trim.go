@@ -0,0 +1,205 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMessagesExceedBudget is returned by TrimMessages when the system messages and
+// the most recent message do not fit in the token budget.
+var ErrMessagesExceedBudget = errors.New("messages do not fit in the token budget")
+
+// TrimmedSummaryPrefix starts the content of the system message TrimMessages puts
+// the summary of the messages it dropped in.
+const TrimmedSummaryPrefix = "Summary of the earlier conversation:\n"
+
+const _defaultTrimSummaryPrompt = `Summarize the following conversation in a few sentences, keeping the facts needed to continue it.
+
+%s
+
+Summary:`
+
+// Summarizer returns a summary of messages.
+type Summarizer func(ctx context.Context, messages []MessageContent) (string, error)
+
+// ModelSummarizer returns a Summarizer that asks the model to summarize the
+// messages.
+func ModelSummarizer(model Model, options ...CallOption) Summarizer {
+	return func(ctx context.Context, messages []MessageContent) (string, error) {
+		prompt := fmt.Sprintf(_defaultTrimSummaryPrompt, messagesText(messages))
+		summary, err := GenerateFromSinglePrompt(ctx, model, prompt, options...)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(summary), nil
+	}
+}
+
+// TrimOptions is a set of options for TrimMessages.
+type TrimOptions struct {
+	// Summarizer, if set, summarizes the messages that are dropped.
+	Summarizer Summarizer
+	// MessageOverhead is the number of tokens every message costs on top of the
+	// tokens of its parts, such as the tokens of its role.
+	MessageOverhead int
+}
+
+// TrimOption is a function that configures TrimOptions.
+type TrimOption func(*TrimOptions)
+
+// WithTrimSummarizer sets the summarizer of the dropped messages. The summary is
+// put in a system message following the system messages, and counts towards the
+// budget.
+func WithTrimSummarizer(summarizer Summarizer) TrimOption {
+	return func(o *TrimOptions) {
+		o.Summarizer = summarizer
+	}
+}
+
+// WithTrimMessageOverhead sets the number of tokens every message costs on top of
+// the tokens of its parts.
+func WithTrimMessageOverhead(tokens int) TrimOption {
+	return func(o *TrimOptions) {
+		o.MessageOverhead = tokens
+	}
+}
+
+// TrimMessages fits messages into a budget of maxTokens tokens, as counted by
+// countTokens, by dropping the oldest messages. The system messages at the start
+// of the messages are always kept.
+//
+// A message with tool calls and the messages with the responses to them that
+// follow it are dropped together, so a ToolCallResponse never loses its ToolCall.
+// Text, image URL, tool call and tool call response parts are counted; binary
+// parts are not.
+//
+// The messages are returned unchanged if they fit. ErrMessagesExceedBudget is
+// returned if the system messages and the most recent message, with its tool
+// calls, do not fit.
+func TrimMessages(
+	ctx context.Context,
+	messages []MessageContent,
+	maxTokens int,
+	countTokens func(text string) int,
+	options ...TrimOption,
+) ([]MessageContent, error) {
+	opts := TrimOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	count := func(messages []MessageContent) int {
+		total := 0
+		for _, message := range messages {
+			total += opts.MessageOverhead
+			for _, text := range partTexts(message.Parts) {
+				total += countTokens(text)
+			}
+		}
+		return total
+	}
+
+	system := 0
+	for system < len(messages) && messages[system].Role == ChatMessageTypeSystem {
+		system++
+	}
+	head, rest := messages[:system], messages[system:]
+	groups := messageGroups(rest)
+
+	// dropped is the number of groups dropped from the start of the rest.
+	dropped, tokens := 0, count(messages)
+	for tokens > maxTokens && dropped < len(groups)-1 {
+		tokens -= count(groups[dropped])
+		dropped++
+	}
+	if tokens > maxTokens {
+		return nil, ErrMessagesExceedBudget
+	}
+	if dropped == 0 {
+		return messages, nil
+	}
+
+	// the summary takes tokens too, so more groups may have to be summarized.
+	for summarized := dropped; opts.Summarizer != nil && summarized < len(groups); summarized++ {
+		summary, err := opts.Summarizer(ctx, flatten(groups[:summarized]))
+		if err != nil {
+			return nil, err
+		}
+		summaryMessage := MessageContent{
+			Role:  ChatMessageTypeSystem,
+			Parts: []ContentPart{TextPart(TrimmedSummaryPrefix + summary)},
+		}
+		trimmed := append(append(append([]MessageContent{}, head...), summaryMessage), flatten(groups[summarized:])...)
+		if count(trimmed) <= maxTokens {
+			return trimmed, nil
+		}
+	}
+
+	return append(append([]MessageContent{}, head...), flatten(groups[dropped:])...), nil
+}
+
+// messageGroups splits messages into the groups that are dropped together: a
+// message with tool calls and the messages with tool call responses following it,
+// or a single message otherwise.
+func messageGroups(messages []MessageContent) [][]MessageContent {
+	groups := make([][]MessageContent, 0, len(messages))
+	for i := 0; i < len(messages); {
+		end := i + 1
+		if hasPart[ToolCall](messages[i]) {
+			for end < len(messages) && hasPart[ToolCallResponse](messages[end]) {
+				end++
+			}
+		}
+		groups = append(groups, messages[i:end])
+		i = end
+	}
+	return groups
+}
+
+func hasPart[T ContentPart](message MessageContent) bool {
+	for _, part := range message.Parts {
+		if _, ok := part.(T); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func flatten(groups [][]MessageContent) []MessageContent {
+	messages := make([]MessageContent, 0, len(groups))
+	for _, group := range groups {
+		messages = append(messages, group...)
+	}
+	return messages
+}
+
+// partTexts returns the texts of the parts that are counted as tokens.
+func partTexts(parts []ContentPart) []string {
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		switch p := part.(type) {
+		case TextContent:
+			texts = append(texts, p.Text)
+		case ImageURLContent:
+			texts = append(texts, p.URL)
+		case ToolCall:
+			if p.FunctionCall != nil {
+				texts = append(texts, p.FunctionCall.Name, p.FunctionCall.Arguments)
+			}
+		case ToolCallResponse:
+			texts = append(texts, p.Name, p.Content)
+		}
+	}
+	return texts
+}
+
+// messagesText renders messages as lines of "role: text".
+func messagesText(messages []MessageContent) string {
+	lines := make([]string, 0, len(messages))
+	for _, message := range messages {
+		lines = append(lines, fmt.Sprintf("%s: %s", message.Role, strings.Join(partTexts(message.Parts), " ")))
+	}
+	return strings.Join(lines, "\n")
+}
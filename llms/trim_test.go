@@ -0,0 +1,141 @@
+package llms_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func textMessage(role llms.ChatMessageType, text string) llms.MessageContent {
+	return llms.MessageContent{Role: role, Parts: []llms.ContentPart{llms.TextPart(text)}}
+}
+
+func TestTrimMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	messages := []llms.MessageContent{
+		textMessage(llms.ChatMessageTypeSystem, "be brief"),
+		textMessage(llms.ChatMessageTypeHuman, "one two three"),
+		textMessage(llms.ChatMessageTypeAI, "four five"),
+		textMessage(llms.ChatMessageTypeHuman, "six"),
+	}
+
+	trimmed, err := llms.TrimMessages(ctx, messages, 8, countWords)
+	require.NoError(t, err)
+	require.Equal(t, messages, trimmed)
+
+	trimmed, err = llms.TrimMessages(ctx, messages, 7, countWords)
+	require.NoError(t, err)
+	require.Equal(t, []llms.MessageContent{messages[0], messages[2], messages[3]}, trimmed)
+
+	trimmed, err = llms.TrimMessages(ctx, messages, 3, countWords)
+	require.NoError(t, err)
+	require.Equal(t, []llms.MessageContent{messages[0], messages[3]}, trimmed)
+
+	// every message costs one token more.
+	trimmed, err = llms.TrimMessages(ctx, messages, 5, countWords, llms.WithTrimMessageOverhead(1))
+	require.NoError(t, err)
+	require.Equal(t, []llms.MessageContent{messages[0], messages[3]}, trimmed)
+
+	_, err = llms.TrimMessages(ctx, messages, 2, countWords)
+	require.ErrorIs(t, err, llms.ErrMessagesExceedBudget)
+}
+
+func TestTrimMessagesToolCalls(t *testing.T) {
+	t.Parallel()
+
+	messages := []llms.MessageContent{
+		textMessage(llms.ChatMessageTypeHuman, "weather in Berlin and Paris"),
+		{Role: llms.ChatMessageTypeAI, Parts: []llms.ContentPart{
+			llms.ToolCall{ID: "1", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: "Berlin"}},
+			llms.ToolCall{ID: "2", Type: "function", FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: "Paris"}},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "1", Name: "weather", Content: "sunny"},
+		}},
+		{Role: llms.ChatMessageTypeTool, Parts: []llms.ContentPart{
+			llms.ToolCallResponse{ToolCallID: "2", Name: "weather", Content: "rainy"},
+		}},
+		textMessage(llms.ChatMessageTypeAI, "sunny in Berlin, rainy in Paris"),
+	}
+
+	// keeping the tool responses without their tool calls would fit.
+	trimmed, err := llms.TrimMessages(context.Background(), messages, 13, countWords)
+	require.NoError(t, err)
+	require.Equal(t, messages[4:], trimmed)
+
+	trimmed, err = llms.TrimMessages(context.Background(), messages, 14, countWords)
+	require.NoError(t, err)
+	require.Equal(t, messages[1:], trimmed)
+}
+
+func TestTrimMessagesSummarizer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	messages := []llms.MessageContent{
+		textMessage(llms.ChatMessageTypeSystem, "be brief"),
+		textMessage(llms.ChatMessageTypeHuman, "my name is Alice"),
+		textMessage(llms.ChatMessageTypeAI, "hello Alice, how can I help"),
+		textMessage(llms.ChatMessageTypeHuman, "what is my name"),
+	}
+
+	var summarized [][]llms.MessageContent
+	summarizer := func(_ context.Context, messages []llms.MessageContent) (string, error) {
+		summarized = append(summarized, messages)
+		return "name Alice", nil
+	}
+
+	// the summary needs the budget of one more message.
+	trimmed, err := llms.TrimMessages(ctx, messages, 13, countWords, llms.WithTrimSummarizer(summarizer))
+	require.NoError(t, err)
+	require.Equal(t, []llms.MessageContent{
+		messages[0],
+		textMessage(llms.ChatMessageTypeSystem, llms.TrimmedSummaryPrefix+"name Alice"),
+		messages[3],
+	}, trimmed)
+	require.Equal(t, [][]llms.MessageContent{messages[1:2], messages[1:3]}, summarized)
+
+	// without room for the summary, the messages are only dropped.
+	trimmed, err = llms.TrimMessages(ctx, messages, 6, countWords, llms.WithTrimSummarizer(summarizer))
+	require.NoError(t, err)
+	require.Equal(t, []llms.MessageContent{messages[0], messages[3]}, trimmed)
+}
+
+func TestModelSummarizer(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLLM{response: " Alice said hello. "}
+	summary, err := llms.ModelSummarizer(llm)(context.Background(), []llms.MessageContent{
+		textMessage(llms.ChatMessageTypeHuman, "hello, I am Alice"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Alice said hello.", summary)
+	require.Contains(t, llm.prompt, "human: hello, I am Alice")
+}
+
+type testLLM struct {
+	response string
+	prompt   string
+}
+
+func (l *testLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l *testLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	_ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	l.prompt = messages[0].Parts[0].(llms.TextContent).Text
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: l.response}}}, nil
+}
@@ -48,6 +48,8 @@ func (h *ChatMessageHistory) AddMessage(_ context.Context, message llms.ChatMess
 }
 
 func (h *ChatMessageHistory) SetMessages(_ context.Context, messages []llms.ChatMessage) error {
-	h.messages = messages
+	// copy the messages so appending to the history does not write into the
+	// caller's slice.
+	h.messages = append([]llms.ChatMessage(nil), messages...)
 	return nil
 }
@@ -44,3 +44,29 @@ func TestChatMessageHistory(t *testing.T) {
 		llms.HumanChatMessage{Content: "zoo"},
 	}, messages)
 }
+
+func TestChatMessageHistoryToolMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	messages := []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "what is 1+1?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"expression":"1+1"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "2"},
+		llms.GenericChatMessage{Role: "moderator", Name: "bob", Content: "stay on topic"},
+	}
+
+	h := NewChatMessageHistory()
+	require.NoError(t, h.SetMessages(ctx, messages[:2]))
+	for _, message := range messages[2:] {
+		require.NoError(t, h.AddMessage(ctx, message))
+	}
+
+	got, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, messages, got)
+}
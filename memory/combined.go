@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrDuplicateMemoryVariable is returned when memories combined load the same variable.
+var ErrDuplicateMemoryVariable = errors.New("duplicate memory variable")
+
+// Combined is a memory combining the variables of several memories, such as a buffer
+// memory for the most recent turns and a vector store memory for relevant past ones.
+type Combined struct {
+	Memories []schema.Memory
+}
+
+// Statically assert that Combined implement the memory interface.
+var _ schema.Memory = Combined{}
+
+// NewCombined is a function for creating a new combined memory. The memories must
+// load different variables.
+func NewCombined(ctx context.Context, memories ...schema.Memory) (Combined, error) {
+	seen := make(map[string]bool)
+	for _, memory := range memories {
+		for _, variable := range memory.MemoryVariables(ctx) {
+			if seen[variable] {
+				return Combined{}, fmt.Errorf("%w: %s", ErrDuplicateMemoryVariable, variable)
+			}
+			seen[variable] = true
+		}
+	}
+	return Combined{Memories: memories}, nil
+}
+
+// GetMemoryKey returns the memory key of the first memory.
+func (m Combined) GetMemoryKey(ctx context.Context) string {
+	if len(m.Memories) == 0 {
+		return ""
+	}
+	return m.Memories[0].GetMemoryKey(ctx)
+}
+
+// MemoryVariables returns the variables of all the memories.
+func (m Combined) MemoryVariables(ctx context.Context) []string {
+	variables := make([]string, 0, len(m.Memories))
+	for _, memory := range m.Memories {
+		variables = append(variables, memory.MemoryVariables(ctx)...)
+	}
+	return variables
+}
+
+// LoadMemoryVariables returns the variables loaded by all the memories.
+func (m Combined) LoadMemoryVariables(ctx context.Context, inputs map[string]any) (map[string]any, error) {
+	values := make(map[string]any)
+	for _, memory := range m.Memories {
+		loaded, err := memory.LoadMemoryVariables(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range loaded {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// SaveContext saves the context of the model run to all the memories.
+func (m Combined) SaveContext(ctx context.Context, inputs map[string]any, outputs map[string]any) error {
+	for _, memory := range m.Memories {
+		if err := memory.SaveContext(ctx, inputs, outputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear clears all the memories.
+func (m Combined) Clear(ctx context.Context) error {
+	for _, memory := range m.Memories {
+		if err := memory.Clear(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,5 +5,10 @@ a variety of implementations for storing and retrieving that data.
 The main components of this package are:
 - ChatMessageHistory: a struct that stores chat messages.
 - ConversationBuffer: a simple form of memory that remembers previous conversational back and forth directly.
+- ConversationSummary: a memory that keeps a running summary of the conversation written by an LLM.
+- ConversationSummaryBuffer: a memory that keeps recent messages and folds older ones into a summary.
+- VectorStoreMemory: a long-term memory that loads the past exchanges most relevant to the input from a vector store.
+- ConversationEntity: a memory that keeps a summary of every entity of the conversation in an EntityStore.
+- Combined: a memory combining the variables of several memories.
 */
 package memory
@@ -0,0 +1,365 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// defaultEntityWindowSize is the default number of previous turns the entities
+	// are extracted and summarized with.
+	defaultEntityWindowSize = 3
+	// noEntities is the answer of the extraction prompt when there are no entities.
+	noEntities = "NONE"
+)
+
+const _defaultEntityExtractionTemplate = `You are an AI assistant reading the transcript of a conversation between an AI and a human. Extract all of the proper nouns from the last line of conversation. As a guideline, a proper noun is generally capitalized. You should definitely extract all names and places.
+
+The conversation history is provided just in case of a coreference (e.g. "What do you know about him" where "him" is defined in a previous line) -- ignore items mentioned there that are not in the last line.
+
+Return the output as a single comma-separated list, or NONE if there is nothing of note to return.
+
+Conversation history (for reference only):
+{{.history}}
+Last line of conversation (for extraction):
+{{.human_prefix}}: {{.input}}
+
+Output:`
+
+const _defaultEntitySummarizationTemplate = `You are an AI assistant helping a human keep track of facts about relevant people, places, and concepts in their life. Update the summary of the provided entity in the "Entity" section based on the last line of your conversation with the human. If you are writing the summary for the first time, return a single sentence.
+The update should only include facts that are relayed in the last line of conversation about the provided entity, and should only contain facts about the provided entity.
+
+If there is no new information about the provided entity or the information is not worth noting (not an important or relevant fact to remember long-term), return the existing summary unchanged.
+
+Full conversation history (for context):
+{{.history}}
+
+Entity to summarize:
+{{.entity}}
+
+Existing summary of {{.entity}}:
+{{.summary}}
+
+Last line of conversation:
+{{.human_prefix}}: {{.input}}
+Updated summary:`
+
+// DefaultEntityExtractionPrompt is the default prompt for extracting the entities of
+// an input. It has the input variables "history", "human_prefix" and "input".
+func DefaultEntityExtractionPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultEntityExtractionTemplate, []string{"history", "human_prefix", "input"})
+}
+
+// DefaultEntitySummarizationPrompt is the default prompt for updating the summary of
+// an entity. It has the input variables "history", "entity", "summary",
+// "human_prefix" and "input".
+func DefaultEntitySummarizationPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(
+		_defaultEntitySummarizationTemplate,
+		[]string{"history", "entity", "summary", "human_prefix", "input"},
+	)
+}
+
+// EntityStore is the interface for storing the summaries of entities.
+type EntityStore interface {
+	// Get returns the summaries of the entities that have one.
+	Get(ctx context.Context, entities []string) (map[string]string, error)
+	// Set creates or replaces the summary of an entity.
+	Set(ctx context.Context, entity string, summary string) error
+	// Delete removes the summary of an entity.
+	Delete(ctx context.Context, entity string) error
+	// Clear removes the summaries of all entities.
+	Clear(ctx context.Context) error
+}
+
+// InMemoryEntityStore is an EntityStore that keeps summaries in memory. It is safe
+// for concurrent use.
+type InMemoryEntityStore struct {
+	mu        sync.RWMutex
+	summaries map[string]string
+}
+
+// Statically assert that InMemoryEntityStore implement the entity store interface.
+var _ EntityStore = &InMemoryEntityStore{}
+
+// NewInMemoryEntityStore creates a new in-memory entity store.
+func NewInMemoryEntityStore() *InMemoryEntityStore {
+	return &InMemoryEntityStore{summaries: make(map[string]string)}
+}
+
+// Get returns the summaries of the entities that have one.
+func (s *InMemoryEntityStore) Get(_ context.Context, entities []string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summaries := make(map[string]string, len(entities))
+	for _, entity := range entities {
+		if summary, ok := s.summaries[entity]; ok {
+			summaries[entity] = summary
+		}
+	}
+	return summaries, nil
+}
+
+// Set creates or replaces the summary of an entity.
+func (s *InMemoryEntityStore) Set(_ context.Context, entity string, summary string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.summaries[entity] = summary
+	return nil
+}
+
+// Delete removes the summary of an entity.
+func (s *InMemoryEntityStore) Delete(_ context.Context, entity string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.summaries, entity)
+	return nil
+}
+
+// Clear removes the summaries of all entities.
+func (s *InMemoryEntityStore) Clear(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.summaries = make(map[string]string)
+	return nil
+}
+
+// ConversationEntity is a memory that keeps a summary of every entity, such as a
+// person or a place, mentioned in the conversation. An LLM extracts the entities of
+// every input and updates their summaries in an entity store once the turn is saved.
+//
+// LoadMemoryVariables returns the last turns of the conversation under MemoryKey, like
+// ConversationWindowBuffer, and the summaries of the entities of the input under
+// EntitiesKey, one "entity: summary" line per entity.
+type ConversationEntity struct {
+	ConversationBuffer
+	LLM         llms.Model
+	EntityStore EntityStore
+	// EntitiesKey is the memory key of the summaries of the entities.
+	EntitiesKey string
+	// WindowSize is the number of previous turns loaded and used as context to
+	// extract and summarize entities.
+	WindowSize int
+	// ExtractionPrompt extracts the entities of an input.
+	ExtractionPrompt prompts.PromptTemplate
+	// SummarizationPrompt updates the summary of an entity.
+	SummarizationPrompt prompts.PromptTemplate
+
+	mu sync.Mutex
+	// entities are the entities of the inputs loaded and not saved yet, by input.
+	entities map[string][]string
+}
+
+// Statically assert that ConversationEntity implement the memory interface.
+var _ schema.Memory = &ConversationEntity{}
+
+// NewConversationEntity is a function for creating a new entity memory. If the
+// entity store is nil, summaries are kept in memory.
+func NewConversationEntity(
+	llm llms.Model,
+	entityStore EntityStore,
+	options ...ConversationBufferOption,
+) *ConversationEntity {
+	if entityStore == nil {
+		entityStore = NewInMemoryEntityStore()
+	}
+	return &ConversationEntity{
+		ConversationBuffer:  *applyBufferOptions(options...),
+		LLM:                 llm,
+		EntityStore:         entityStore,
+		EntitiesKey:         "entities",
+		WindowSize:          defaultEntityWindowSize,
+		ExtractionPrompt:    DefaultEntityExtractionPrompt(),
+		SummarizationPrompt: DefaultEntitySummarizationPrompt(),
+	}
+}
+
+// MemoryVariables returns the memory key and the entities key.
+func (m *ConversationEntity) MemoryVariables(context.Context) []string {
+	return []string{m.MemoryKey, m.EntitiesKey}
+}
+
+// LoadMemoryVariables extracts the entities of the input and returns their summaries
+// with the last turns of the conversation.
+func (m *ConversationEntity) LoadMemoryVariables(
+	ctx context.Context,
+	inputs map[string]any,
+) (map[string]any, error) {
+	input, err := GetInputValue(inputs, m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := m.recentMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := m.extractEntities(ctx, history, input)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	if m.entities == nil {
+		m.entities = make(map[string][]string)
+	}
+	m.entities[input] = entities
+	m.mu.Unlock()
+
+	summaries, err := m.EntityStore.Get(ctx, entities)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0, len(summaries))
+	for _, entity := range entities {
+		if summary, ok := summaries[entity]; ok && summary != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", entity, summary))
+		}
+	}
+
+	var historyValue any = history
+	if m.ReturnMessages {
+		historyValue = messages
+	}
+	return map[string]any{
+		m.MemoryKey:   historyValue,
+		m.EntitiesKey: strings.Join(lines, "\n"),
+	}, nil
+}
+
+// SaveContext saves the turn to the chat history and updates the summaries of the
+// entities of the input. The entities are those extracted when the input was
+// loaded with LoadMemoryVariables, or extracted from the input if it was not.
+func (m *ConversationEntity) SaveContext(
+	ctx context.Context,
+	inputValues map[string]any,
+	outputValues map[string]any,
+) error {
+	input, err := GetInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+
+	messages, err := m.recentMessages(ctx)
+	if err != nil {
+		return err
+	}
+	history, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	entities, ok := m.entities[input]
+	delete(m.entities, input)
+	m.mu.Unlock()
+	if !ok {
+		entities, err = m.extractEntities(ctx, history, input)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := m.ConversationBuffer.SaveContext(ctx, inputValues, outputValues); err != nil {
+		return err
+	}
+
+	summaries, err := m.EntityStore.Get(ctx, entities)
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		prompt, err := m.SummarizationPrompt.Format(map[string]any{
+			"history":      history,
+			"entity":       entity,
+			"summary":      summaries[entity],
+			"human_prefix": m.HumanPrefix,
+			"input":        input,
+		})
+		if err != nil {
+			return err
+		}
+		summary, err := llms.GenerateFromSinglePrompt(ctx, m.LLM, prompt)
+		if err != nil {
+			return err
+		}
+		if err := m.EntityStore.Set(ctx, entity, strings.TrimSpace(summary)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Clear removes the messages of the chat history and the summaries of all entities.
+func (m *ConversationEntity) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	m.entities = nil
+	m.mu.Unlock()
+
+	if err := m.ConversationBuffer.Clear(ctx); err != nil {
+		return err
+	}
+	return m.EntityStore.Clear(ctx)
+}
+
+// recentMessages returns the messages of the last WindowSize turns.
+func (m *ConversationEntity) recentMessages(ctx context.Context) ([]llms.ChatMessage, error) {
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if size := m.WindowSize * defaultMessageSize; len(messages) > size {
+		messages = messages[len(messages)-size:]
+	}
+	return messages, nil
+}
+
+// extractEntities returns the entities the LLM finds in the input, sorted and
+// without duplicates.
+func (m *ConversationEntity) extractEntities(ctx context.Context, history, input string) ([]string, error) {
+	prompt, err := m.ExtractionPrompt.Format(map[string]any{
+		"history":      history,
+		"human_prefix": m.HumanPrefix,
+		"input":        input,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output, err := llms.GenerateFromSinglePrompt(ctx, m.LLM, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	output = strings.TrimSpace(output)
+	entities := make([]string, 0)
+	if output == "" || strings.EqualFold(output, noEntities) {
+		return entities, nil
+	}
+	seen := make(map[string]bool)
+	for _, entity := range strings.Split(output, ",") {
+		entity = strings.TrimSpace(entity)
+		if entity == "" || strings.EqualFold(entity, noEntities) || seen[entity] {
+			continue
+		}
+		seen[entity] = true
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+	return entities, nil
+}
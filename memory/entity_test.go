@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// entityLLM extracts the capitalized words of the input as entities, and summarizes
+// an entity by appending the input to its summary. The input is read after the
+// human prefix given.
+type entityLLM struct {
+	humanPrefix string
+}
+
+func (l entityLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l entityLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	_ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	prompt := messages[0].Parts[0].(llms.TextContent).Text //nolint:forcetypeassert
+
+	var content string
+	if strings.Contains(prompt, "Extract all of the proper nouns") {
+		input := between(prompt, "(for extraction):\n"+l.humanPrefix+": ", "\n")
+		var entities []string
+		for _, word := range strings.Fields(input) {
+			if word = strings.Trim(word, ".,?!"); word != "" && word[0] >= 'A' && word[0] <= 'Z' && word != "I" {
+				entities = append(entities, word)
+			}
+		}
+		content = noEntities
+		if len(entities) > 0 {
+			content = strings.Join(entities, ", ")
+		}
+	} else {
+		entity := between(prompt, "Entity to summarize:\n", "\n")
+		summary := between(prompt, "Existing summary of "+entity+":\n", "\n")
+		input := between(prompt, "Last line of conversation:\n"+l.humanPrefix+": ", "\n")
+		content = strings.TrimSpace(summary + " " + input)
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content}}}, nil
+}
+
+func TestConversationEntity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := NewInMemoryEntityStore()
+	m := NewConversationEntity(entityLLM{humanPrefix: "Human"}, store)
+	assert.Equal(t, []string{"history", "entities"}, m.MemoryVariables(ctx))
+
+	inputs := map[string]any{"input": "Alice works with Bob in Paris."}
+	result, err := m.LoadMemoryVariables(ctx, inputs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "", "entities": ""}, result)
+	require.NoError(t, m.SaveContext(ctx, inputs, map[string]any{"output": "Nice."}))
+
+	summaries, err := store.Get(ctx, []string{"Alice", "Bob", "Paris", "Carol"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Alice": "Alice works with Bob in Paris.",
+		"Bob":   "Alice works with Bob in Paris.",
+		"Paris": "Alice works with Bob in Paris.",
+	}, summaries)
+
+	// entities are extracted from the input on save if it was not loaded
+	inputs = map[string]any{"input": "Alice likes tea."}
+	require.NoError(t, m.SaveContext(ctx, inputs, map[string]any{"output": "Noted."}))
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "What does Alice drink?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history":  "Human: Alice works with Bob in Paris.\nAI: Nice.\nHuman: Alice likes tea.\nAI: Noted.",
+		"entities": "Alice: Alice works with Bob in Paris. Alice likes tea.",
+	}, result)
+
+	require.NoError(t, m.Clear(ctx))
+	summaries, err = store.Get(ctx, []string{"Alice"})
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
+
+func TestConversationEntityInputs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := NewInMemoryEntityStore()
+	m := NewConversationEntity(entityLLM{humanPrefix: "User"}, store, WithHumanPrefix("User"))
+
+	// the entities loaded for an input are not used to save another input
+	_, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "Alice is here."})
+	require.NoError(t, err)
+	_, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "Bob is there."})
+	require.NoError(t, err)
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "Alice is here."}, map[string]any{"output": "Hi."}))
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "Carol is away."}, map[string]any{"output": "Ok."}))
+
+	summaries, err := store.Get(ctx, []string{"Alice", "Bob", "Carol"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Alice": "Alice is here.",
+		"Carol": "Carol is away.",
+	}, summaries)
+}
@@ -75,7 +75,9 @@ func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage,
 		if err := json.Unmarshal([]byte(message.History), &m); err != nil {
 			return messages, err
 		}
-		messages = append(messages, m.ToChatMessage())
+		if message := m.ToChatMessage(); message != nil {
+			messages = append(messages, message)
+		}
 	}
 
 	return messages, nil
@@ -130,6 +132,9 @@ func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.Ch
 	if err := h.Clear(ctx); err != nil {
 		return err
 	}
+	if len(_messages) == 0 {
+		return nil
+	}
 
 	_, err := h.collection.InsertMany(ctx, _messages)
 	return err
@@ -65,3 +65,33 @@ func TestMongoDBChatMessageHistory(t *testing.T) {
 		require.NoError(t, history.Clear(ctx))
 	})
 }
+
+func TestMongoDBChatMessageHistoryToolMessages(t *testing.T) {
+	t.Parallel()
+
+	url, err := runTestContainer()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	history, err := NewMongoDBChatMessageHistory(ctx, WithConnectionURL(url), WithSessionID("testToolSession"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, history.Clear(ctx))
+	})
+
+	messages := []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "what is 1+1?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"expression":"1+1"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "2"},
+		llms.GenericChatMessage{Role: "moderator", Name: "bob", Content: "stay on topic"},
+	}
+	require.NoError(t, history.SetMessages(ctx, messages))
+
+	got, err := history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, messages, got)
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultTableName is the default name of the messages table.
+	DefaultTableName = "langchaingo_messages"
+	// DefaultSessionID is the session id of a history created without one.
+	DefaultSessionID = "default"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// ChatMessageHistoryOption is a function for creating a new chat message
+// history with other than the default values.
+type ChatMessageHistoryOption func(h *ChatMessageHistory)
+
+// WithConnectionURL is an option for specifying the Postgres connection URL. The
+// history opens a pgxpool.Pool for it, closed by Close. Either this or WithConn
+// must be used.
+func WithConnectionURL(connectionURL string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.connURL = connectionURL
+	}
+}
+
+// WithConn is an option for specifying the Postgres connection, such as a
+// pgxpool.Pool shared by several histories. Either this or WithConnectionURL
+// must be used.
+func WithConn(conn PGXConn) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.conn = conn
+	}
+}
+
+// WithTableName is an option for setting the name of the messages table.
+func WithTableName(name string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.tableName = name
+	}
+}
+
+// WithSessionID is an option for setting the id of the conversation, such as
+// a user name or a chat id. Histories with different session ids can share a
+// table.
+func WithSessionID(sessionID string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.sessionID = sessionID
+	}
+}
+
+// WithLimit is an option for only returning the most recent messages of the
+// session. Zero, the default, returns all messages.
+func WithLimit(limit int) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.limit = limit
+	}
+}
+
+// WithTTL is an option for expiring every message once the duration passed
+// since it was added. Expired messages are not returned and are deleted by
+// the next write to the session. Zero, the default, keeps messages forever.
+func WithTTL(ttl time.Duration) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.ttl = ttl
+	}
+}
+
+func applyChatOptions(options ...ChatMessageHistoryOption) (*ChatMessageHistory, error) {
+	h := &ChatMessageHistory{
+		tableName: DefaultTableName,
+		sessionID: DefaultSessionID,
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	if h.conn == nil && h.connURL == "" {
+		return nil, fmt.Errorf("%w: missing postgres connection", ErrInvalidOptions)
+	}
+	if h.sessionID == "" {
+		return nil, fmt.Errorf("%w: empty session id", ErrInvalidOptions)
+	}
+	if h.limit < 0 {
+		return nil, fmt.Errorf("%w: negative limit", ErrInvalidOptions)
+	}
+	if h.ttl < 0 {
+		return nil, fmt.Errorf("%w: negative ttl", ErrInvalidOptions)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,226 @@
+// Package postgres adds support for chat message history using Postgres.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
+type PGXConn interface {
+	Ping(ctx context.Context) error
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error)
+}
+
+// CloseNoErr is implemented by connections, such as a pgxpool.Pool, that close
+// without an error.
+type CloseNoErr interface {
+	Close()
+}
+
+// ChatMessageHistory is a chat message history that stores the messages of a
+// session in a Postgres table. Messages are ordered by a sequence, so several
+// replicas can add messages to the same session at once, and SetMessages locks
+// the session while it replaces its messages.
+type ChatMessageHistory struct {
+	conn      PGXConn
+	connURL   string
+	tableName string
+	sessionID string
+	limit     int
+	ttl       time.Duration
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// NewChatMessageHistory creates a new ChatMessageHistory and creates its table
+// if it does not exist.
+func NewChatMessageHistory(ctx context.Context, options ...ChatMessageHistoryOption) (*ChatMessageHistory, error) {
+	h, err := applyChatOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	if h.conn == nil {
+		pool, err := pgxpool.New(ctx, h.connURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := pool.Ping(ctx); err != nil {
+			pool.Close()
+			return nil, err
+		}
+		h.conn = pool
+	} else if err := h.conn.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	schema := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id BIGSERIAL PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	type TEXT NOT NULL,
+	content TEXT NOT NULL,
+	data JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	expires_at TIMESTAMPTZ);
+CREATE INDEX IF NOT EXISTS %[1]s_session_id ON %[1]s (session_id, id);`, h.tableName)
+	if _, err := h.conn.Exec(ctx, schema); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Close closes the connection, or the pool opened for the connection URL.
+func (h *ChatMessageHistory) Close() error {
+	switch conn := h.conn.(type) {
+	case *pgx.Conn:
+		return conn.Close(context.Background())
+	case io.Closer:
+		return conn.Close()
+	case CloseNoErr:
+		conn.Close()
+	}
+	return nil
+}
+
+// DropTable drops the messages table, removing the messages of all sessions.
+func (h *ChatMessageHistory) DropTable(ctx context.Context) error {
+	_, err := h.conn.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, h.tableName))
+	return err
+}
+
+// Messages returns the messages of the session that have not expired, oldest
+// first. With a limit, only the most recent messages are returned.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	sql := fmt.Sprintf(`SELECT id, data FROM %s
+		WHERE session_id = $1 AND (expires_at IS NULL OR expires_at > now())
+		ORDER BY id DESC`, h.tableName)
+	args := []any{h.sessionID}
+	if h.limit > 0 {
+		sql += " LIMIT $2"
+		args = append(args, h.limit)
+	}
+	sql = fmt.Sprintf("SELECT data FROM (%s) AS recent ORDER BY id ASC", sql)
+
+	rows, err := h.conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]llms.ChatMessage, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var model llms.ChatMessageModel
+		if err := json.Unmarshal(data, &model); err != nil {
+			return nil, err
+		}
+		if message := model.ToChatMessage(); message != nil {
+			messages = append(messages, message)
+		}
+	}
+	return messages, rows.Err()
+}
+
+// AddMessage adds a message to the session, and deletes its expired messages.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	if err := h.deleteExpired(ctx); err != nil {
+		return err
+	}
+	sql, args, err := h.insert(message)
+	if err != nil {
+		return err
+	}
+	_, err = h.conn.Exec(ctx, sql, args...)
+	return err
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// Clear removes all messages of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	_, err := h.conn.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1`, h.tableName), h.sessionID)
+	return err
+}
+
+// SetMessages replaces the messages of the session in a transaction. Concurrent
+// calls for the same session are run one after the other.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	tx, err := h.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	// the lock is held until the transaction ends.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`,
+		h.tableName+"/"+h.sessionID); err != nil {
+		return err
+	}
+
+	b := &pgx.Batch{}
+	b.Queue(fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1`, h.tableName), h.sessionID)
+	for _, message := range messages {
+		sql, args, err := h.insert(message)
+		if err != nil {
+			return err
+		}
+		b.Queue(sql, args...)
+	}
+	if err := tx.SendBatch(ctx, b).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// insert returns the statement and the arguments inserting a message.
+func (h *ChatMessageHistory) insert(message llms.ChatMessage) (string, []any, error) {
+	data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+	if err != nil {
+		return "", nil, err
+	}
+
+	// a null ttl leaves the expiry null.
+	var ttl *float64
+	if h.ttl > 0 {
+		seconds := h.ttl.Seconds()
+		ttl = &seconds
+	}
+	sql := fmt.Sprintf(`INSERT INTO %s (session_id, type, content, data, expires_at)
+		VALUES ($1, $2, $3, $4, now() + make_interval(secs => $5))`, h.tableName)
+	return sql, []any{h.sessionID, string(message.GetType()), message.GetContent(), string(data), ttl}, nil
+}
+
+// deleteExpired deletes the expired messages of the session.
+func (h *ChatMessageHistory) deleteExpired(ctx context.Context) error {
+	if h.ttl == 0 {
+		return nil
+	}
+	_, err := h.conn.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1 AND expires_at <= now()`,
+		h.tableName), h.sessionID)
+	return err
+}
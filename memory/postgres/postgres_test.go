@@ -0,0 +1,208 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory/postgres"
+)
+
+func preCheckEnvSetting(t *testing.T) string {
+	t.Helper()
+
+	postgresURL := os.Getenv("POSTGRES_CONNECTION_STRING")
+	if postgresURL == "" {
+		container, err := tcpostgres.RunContainer(
+			context.Background(),
+			testcontainers.WithImage("docker.io/postgres:16"),
+			tcpostgres.WithDatabase("db_test"),
+			tcpostgres.WithUsername("user"),
+			tcpostgres.WithPassword("passw0rd!"),
+			testcontainers.WithWaitStrategy(
+				wait.ForLog("database system is ready to accept connections").
+					WithOccurrence(2).
+					WithStartupTimeout(30*time.Second)),
+		)
+		if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+			t.Skip("Docker not available")
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, container.Terminate(context.Background()))
+		})
+
+		str, err := container.ConnectionString(context.Background(), "sslmode=disable")
+		require.NoError(t, err)
+
+		postgresURL = str
+	}
+
+	return postgresURL
+}
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	url := preCheckEnvSetting(t)
+	h, err := postgres.NewChatMessageHistory(ctx,
+		postgres.WithConnectionURL(url),
+		postgres.WithTableName("test_messages"),
+		postgres.WithSessionID("alice"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, h.DropTable(ctx))
+		require.NoError(t, h.Close())
+	})
+
+	messages := []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "be brief"},
+		llms.HumanChatMessage{Content: "what is 1+1?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"expression":"1+1"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "2"},
+	}
+	require.NoError(t, h.SetMessages(ctx, messages[:2]))
+	for _, message := range messages[2:] {
+		require.NoError(t, h.AddMessage(ctx, message))
+	}
+	got, err := h.Messages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, messages, got)
+
+	// other sessions are kept apart.
+	other, err := postgres.NewChatMessageHistory(ctx,
+		postgres.WithConnectionURL(url),
+		postgres.WithTableName("test_messages"),
+		postgres.WithSessionID("bob"),
+		postgres.WithLimit(2),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, other.Close()) })
+	got, err = other.Messages(ctx)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, other.AddUserMessage(ctx, fmt.Sprint(i)))
+	}
+	got, err = other.Messages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "1"},
+		llms.HumanChatMessage{Content: "2"},
+	}, got)
+
+	require.NoError(t, h.Clear(ctx))
+	got, err = h.Messages(ctx)
+	require.NoError(t, err)
+	require.Empty(t, got)
+	got, err = other.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+}
+
+func TestChatMessageHistoryTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h, err := postgres.NewChatMessageHistory(ctx,
+		postgres.WithConnectionURL(preCheckEnvSetting(t)),
+		postgres.WithTableName("test_messages_ttl"),
+		postgres.WithTTL(time.Second),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, h.DropTable(ctx))
+		require.NoError(t, h.Close())
+	})
+
+	require.NoError(t, h.AddUserMessage(ctx, "foo"))
+	got, err := h.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	time.Sleep(1500 * time.Millisecond)
+	require.NoError(t, h.AddAIMessage(ctx, "bar"))
+	got, err = h.Messages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []llms.ChatMessage{llms.AIChatMessage{Content: "bar"}}, got)
+}
+
+func TestChatMessageHistoryConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, preCheckEnvSetting(t))
+	require.NoError(t, err)
+
+	// every writer stands for a replica with its own history.
+	const writers, messages = 4, 10
+	histories := make([]*postgres.ChatMessageHistory, writers)
+	for i := range histories {
+		histories[i], err = postgres.NewChatMessageHistory(ctx,
+			postgres.WithConn(pool),
+			postgres.WithTableName("test_messages_concurrent"),
+		)
+		require.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		require.NoError(t, histories[0].DropTable(ctx))
+		require.NoError(t, histories[0].Close())
+	})
+
+	var wg sync.WaitGroup
+	for i, h := range histories {
+		wg.Add(1)
+		go func(i int, h *postgres.ChatMessageHistory) {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				require.NoError(t, h.AddUserMessage(ctx, fmt.Sprintf("%d-%d", i, j)))
+			}
+		}(i, h)
+	}
+	wg.Wait()
+
+	got, err := histories[0].Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, writers*messages)
+
+	// the messages of every writer are in the order they were added.
+	next := make([]int, writers)
+	for _, message := range got {
+		var i, j int
+		_, err := fmt.Sscanf(message.GetContent(), "%d-%d", &i, &j)
+		require.NoError(t, err)
+		require.Equal(t, next[i], j)
+		next[i]++
+	}
+}
+
+func TestChatMessageHistoryInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, err := postgres.NewChatMessageHistory(ctx)
+	require.ErrorIs(t, err, postgres.ErrInvalidOptions)
+
+	_, err = postgres.NewChatMessageHistory(ctx,
+		postgres.WithConnectionURL("postgres://localhost:5432/db"),
+		postgres.WithSessionID(""),
+	)
+	require.ErrorIs(t, err, postgres.ErrInvalidOptions)
+}
@@ -0,0 +1,175 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// DefaultKeyPrefix is the default prefix of the keys of the lists the messages
+// of sessions are stored in.
+const DefaultKeyPrefix = "langchaingo:messages:"
+
+// ErrMissingSessionID is returned when a chat message history is created with
+// an empty session id.
+var ErrMissingSessionID = errors.New("missing session id")
+
+// setMessagesScript replaces the messages of a session at once, so concurrent
+// writers never see a partly replaced session.
+var setMessagesScript = rueidis.NewLuaScript(`
+redis.call("DEL", KEYS[1])
+if #ARGV > 1 then
+	redis.call("RPUSH", KEYS[1], unpack(ARGV, 2))
+	local ttl = tonumber(ARGV[1])
+	if ttl > 0 then
+		redis.call("PEXPIRE", KEYS[1], ttl)
+	end
+end
+return 0`)
+
+// ChatMessageHistory is a chat message history that stores the messages of a
+// session in a redis list. Messages are appended atomically, so several replicas
+// can add messages to the same session at once.
+type ChatMessageHistory struct {
+	client    rueidis.Client
+	keyPrefix string
+	key       string
+	limit     int
+	ttl       time.Duration
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// ChatMessageHistoryOption is a function for creating a new chat message
+// history with other than the default values.
+type ChatMessageHistoryOption func(h *ChatMessageHistory)
+
+// WithKeyPrefix is an option for NewChatMessageHistory for setting the prefix
+// the session id is appended to for the key of the list.
+func WithKeyPrefix(prefix string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.keyPrefix = prefix
+	}
+}
+
+// WithLimit is an option for NewChatMessageHistory for only returning the most
+// recent messages of the session. Zero, the default, returns all messages.
+func WithLimit(limit int) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.limit = limit
+	}
+}
+
+// WithTTL is an option for NewChatMessageHistory for expiring the session once
+// no message was added for the duration. Zero, the default, keeps the session
+// forever.
+func WithTTL(ttl time.Duration) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.ttl = ttl
+	}
+}
+
+// NewChatMessageHistory creates a new ChatMessageHistory storing the messages of
+// the session with the client. The client is not closed by the history.
+func NewChatMessageHistory(
+	client rueidis.Client,
+	sessionID string,
+	options ...ChatMessageHistoryOption,
+) (*ChatMessageHistory, error) {
+	if client == nil {
+		return nil, ErrMissingClient
+	}
+	if sessionID == "" {
+		return nil, ErrMissingSessionID
+	}
+
+	h := &ChatMessageHistory{client: client, keyPrefix: DefaultKeyPrefix}
+	for _, option := range options {
+		option(h)
+	}
+	h.key = h.keyPrefix + sessionID
+
+	return h, nil
+}
+
+// Messages returns the messages of the session, oldest first. With a limit, only
+// the most recent messages are returned.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	start := int64(0)
+	if h.limit > 0 {
+		start = -int64(h.limit)
+	}
+	values, err := h.client.Do(ctx, h.client.B().Lrange().Key(h.key).Start(start).Stop(-1).Build()).AsStrSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]llms.ChatMessage, 0, len(values))
+	for _, value := range values {
+		var model llms.ChatMessageModel
+		if err := json.Unmarshal([]byte(value), &model); err != nil {
+			return nil, err
+		}
+		if message := model.ToChatMessage(); message != nil {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// AddMessage adds a message to the session, and renews its expiry.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+	if err != nil {
+		return err
+	}
+
+	cmds := rueidis.Commands{
+		h.client.B().Rpush().Key(h.key).Element(string(data)).Build(),
+	}
+	if h.ttl > 0 {
+		cmds = append(cmds, h.client.B().Pexpire().Key(h.key).Milliseconds(h.ttl.Milliseconds()).Build())
+	}
+	for _, resp := range h.client.DoMulti(ctx, cmds...) {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// Clear removes all messages of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	return h.client.Do(ctx, h.client.B().Del().Key(h.key).Build()).Error()
+}
+
+// SetMessages replaces the messages of the session atomically.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	args := make([]string, 0, len(messages)+1)
+	args = append(args, strconv.FormatInt(h.ttl.Milliseconds(), 10))
+	for _, message := range messages {
+		data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+		if err != nil {
+			return err
+		}
+		args = append(args, string(data))
+	}
+	return setMessagesScript.Exec(ctx, h.client, []string{h.key}, args).Error()
+}
@@ -0,0 +1,149 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory/redis"
+)
+
+func newClient(t *testing.T, url string) rueidis.Client {
+	t.Helper()
+
+	option, err := rueidis.ParseURL(url)
+	require.NoError(t, err)
+	client, err := rueidis.NewClient(option)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t, getRedisURL(t))
+	prefix := redis.WithKeyPrefix("test:messages:" + uuid.NewString() + ":")
+
+	h, err := redis.NewChatMessageHistory(client, "alice", prefix)
+	require.NoError(t, err)
+
+	messages := []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "be brief"},
+		llms.HumanChatMessage{Content: "what is 1+1?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"expression":"1+1"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "2"},
+	}
+	require.NoError(t, h.SetMessages(ctx, messages[:2]))
+	for _, message := range messages[2:] {
+		require.NoError(t, h.AddMessage(ctx, message))
+	}
+	got, err := h.Messages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, messages, got)
+
+	// other sessions are kept apart.
+	other, err := redis.NewChatMessageHistory(client, "bob", prefix, redis.WithLimit(2))
+	require.NoError(t, err)
+	got, err = other.Messages(ctx)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, other.AddUserMessage(ctx, fmt.Sprint(i)))
+	}
+	got, err = other.Messages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "1"},
+		llms.HumanChatMessage{Content: "2"},
+	}, got)
+
+	require.NoError(t, h.Clear(ctx))
+	got, err = h.Messages(ctx)
+	require.NoError(t, err)
+	require.Empty(t, got)
+	require.NoError(t, other.Clear(ctx))
+
+	_, err = redis.NewChatMessageHistory(nil, "alice")
+	require.ErrorIs(t, err, redis.ErrMissingClient)
+	_, err = redis.NewChatMessageHistory(client, "")
+	require.ErrorIs(t, err, redis.ErrMissingSessionID)
+}
+
+func TestChatMessageHistoryTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h, err := redis.NewChatMessageHistory(newClient(t, getRedisURL(t)), uuid.NewString(),
+		redis.WithTTL(500*time.Millisecond))
+	require.NoError(t, err)
+
+	require.NoError(t, h.SetMessages(ctx, []llms.ChatMessage{llms.HumanChatMessage{Content: "foo"}}))
+	require.NoError(t, h.AddAIMessage(ctx, "bar"))
+	got, err := h.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	time.Sleep(time.Second)
+	got, err = h.Messages(ctx)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestChatMessageHistoryConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	url := getRedisURL(t)
+	sessionID := uuid.NewString()
+
+	// every writer stands for a replica with its own client.
+	const writers, messages = 4, 10
+	histories := make([]*redis.ChatMessageHistory, writers)
+	for i := range histories {
+		var err error
+		histories[i], err = redis.NewChatMessageHistory(newClient(t, url), sessionID)
+		require.NoError(t, err)
+	}
+	t.Cleanup(func() {
+		require.NoError(t, histories[0].Clear(context.Background()))
+	})
+
+	var wg sync.WaitGroup
+	for i, h := range histories {
+		wg.Add(1)
+		go func(i int, h *redis.ChatMessageHistory) {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				require.NoError(t, h.AddUserMessage(ctx, fmt.Sprintf("%d-%d", i, j)))
+			}
+		}(i, h)
+	}
+	wg.Wait()
+
+	got, err := histories[0].Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, writers*messages)
+
+	// the messages of every writer are in the order they were added.
+	next := make([]int, writers)
+	for _, message := range got {
+		var i, j int
+		_, err := fmt.Sscanf(message.GetContent(), "%d-%d", &i, &j)
+		require.NoError(t, err)
+		require.Equal(t, next[i], j)
+		next[i]++
+	}
+}
@@ -0,0 +1,146 @@
+// Package redis adds support for
+// storing conversation memory in redis using rueidis.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/tmc/langchaingo/memory"
+)
+
+// DefaultEntityStoreKey is the default key of the hash the summaries of entities
+// are stored in.
+const DefaultEntityStoreKey = "langchaingo:entities"
+
+// ErrMissingClient is returned when neither a client nor a url is given.
+var ErrMissingClient = errors.New("missing redis client or url")
+
+// EntityStore is a memory.EntityStore that stores the summaries of entities in the
+// fields of a redis hash.
+type EntityStore struct {
+	client rueidis.Client
+	url    string
+	key    string
+	ttl    time.Duration
+}
+
+// Statically assert that EntityStore implement the entity store interface.
+var _ memory.EntityStore = &EntityStore{}
+
+// EntityStoreOption is a function for creating a new entity store
+// with other than the default values.
+type EntityStoreOption func(s *EntityStore)
+
+// WithClient is an option for NewEntityStore for using a redis client.
+func WithClient(client rueidis.Client) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.client = client
+	}
+}
+
+// WithURL is an option for NewEntityStore for connecting to redis with
+// a url, such as "redis://localhost:6379/0".
+func WithURL(url string) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.url = url
+	}
+}
+
+// WithEntityStoreKey is an option for NewEntityStore for setting the key
+// of the hash, for example to keep the entities of every user apart.
+func WithEntityStoreKey(key string) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.key = key
+	}
+}
+
+// WithEntityStoreTTL is an option for NewEntityStore for expiring the
+// summaries of all entities once none was updated for the duration.
+func WithEntityStoreTTL(ttl time.Duration) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.ttl = ttl
+	}
+}
+
+// NewEntityStore creates a new EntityStore.
+func NewEntityStore(options ...EntityStoreOption) (*EntityStore, error) {
+	s := &EntityStore{key: DefaultEntityStoreKey}
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.client == nil {
+		if s.url == "" {
+			return nil, ErrMissingClient
+		}
+		clientOption, err := rueidis.ParseURL(s.url)
+		if err != nil {
+			return nil, err
+		}
+		s.client, err = rueidis.NewClient(clientOption)
+		if err != nil {
+			return nil, fmt.Errorf("redis connect: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Get returns the summaries of the entities that have one.
+func (s *EntityStore) Get(ctx context.Context, entities []string) (map[string]string, error) {
+	summaries := make(map[string]string, len(entities))
+	if len(entities) == 0 {
+		return summaries, nil
+	}
+
+	values, err := s.client.Do(ctx, s.client.B().Hmget().Key(s.key).Field(entities...).Build()).ToArray()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		summary, err := value.ToString()
+		if rueidis.IsRedisNil(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		summaries[entities[i]] = summary
+	}
+	return summaries, nil
+}
+
+// Set creates or replaces the summary of an entity.
+func (s *EntityStore) Set(ctx context.Context, entity string, summary string) error {
+	cmds := rueidis.Commands{
+		s.client.B().Hset().Key(s.key).FieldValue().FieldValue(entity, summary).Build(),
+	}
+	if s.ttl > 0 {
+		cmds = append(cmds, s.client.B().Expire().Key(s.key).Seconds(int64(s.ttl.Seconds())).Build())
+	}
+	for _, resp := range s.client.DoMulti(ctx, cmds...) {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the summary of an entity.
+func (s *EntityStore) Delete(ctx context.Context, entity string) error {
+	return s.client.Do(ctx, s.client.B().Hdel().Key(s.key).Field(entity).Build()).Error()
+}
+
+// Clear removes the summaries of all entities.
+func (s *EntityStore) Clear(ctx context.Context) error {
+	return s.client.Do(ctx, s.client.B().Del().Key(s.key).Build()).Error()
+}
+
+// Close closes the redis client.
+func (s *EntityStore) Close() {
+	s.client.Close()
+}
@@ -0,0 +1,42 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/memory/redis"
+)
+
+func TestEntityStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := redis.NewEntityStore(
+		redis.WithURL(getRedisURL(t)),
+		redis.WithEntityStoreKey("test:entities:"+uuid.NewString()),
+	)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Set(ctx, "Alice", "Alice lives in Paris."))
+	require.NoError(t, s.Set(ctx, "Bob", "Bob likes jazz."))
+
+	summaries, err := s.Get(ctx, []string{"Alice", "Bob", "Carol"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Alice": "Alice lives in Paris.", "Bob": "Bob likes jazz."}, summaries)
+
+	require.NoError(t, s.Delete(ctx, "Bob"))
+	summaries, err = s.Get(ctx, []string{"Bob"})
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+
+	require.NoError(t, s.Clear(ctx))
+	summaries, err = s.Get(ctx, []string{"Alice"})
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+
+	_, err = redis.NewEntityStore()
+	require.ErrorIs(t, err, redis.ErrMissingClient)
+}
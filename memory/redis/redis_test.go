@@ -0,0 +1,35 @@
+package redis_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+func getRedisURL(t *testing.T) string {
+	t.Helper()
+
+	url := os.Getenv("REDIS_URL")
+	if url != "" {
+		return url
+	}
+
+	ctx := context.Background()
+	container, err := tcredis.RunContainer(ctx, testcontainers.WithImage("docker.io/redis:7"))
+	if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+		t.Skip("Docker not available")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	})
+
+	url, err = container.ConnectionString(ctx)
+	require.NoError(t, err)
+	return url
+}
@@ -0,0 +1,162 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/internal/sqlitedb"
+	"github.com/tmc/langchaingo/memory"
+)
+
+// DefaultEntityTableName sets a default table name for entity stores.
+const DefaultEntityTableName = "langchaingo_entities"
+
+// DefaultEntitySchema sets a default schema to be run by NewEntityStore.
+const DefaultEntitySchema = `CREATE TABLE IF NOT EXISTS %s (
+		session TEXT NOT NULL,
+		entity TEXT NOT NULL,
+		summary TEXT NOT NULL,
+		updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (session, entity)
+);`
+
+// EntityStore is a memory.EntityStore that stores the summaries of entities in a
+// sqlite3 table.
+type EntityStore struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the entities table.
+	TableName string
+	// Session defines a session name or id the entities belong to.
+	Session string
+}
+
+// Statically assert that EntityStore implement the entity store interface.
+var _ memory.EntityStore = &EntityStore{}
+
+// EntityStoreOption is a function for creating a new entity store
+// with other than the default values.
+type EntityStoreOption func(s *EntityStore)
+
+// WithEntityDB is an option for NewEntityStore for adding a database connection.
+func WithEntityDB(db *sql.DB) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.DB = db
+	}
+}
+
+// WithEntityDBAddress is an option for NewEntityStore for specifying an address
+// or file path for when connecting the db.
+func WithEntityDBAddress(addr string) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.DBAddress = addr
+	}
+}
+
+// WithEntityTableName is an option for NewEntityStore for setting the name of
+// the entities table.
+func WithEntityTableName(name string) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.TableName = name
+	}
+}
+
+// WithEntitySession is an option for NewEntityStore for setting a session name
+// or id the entities belong to.
+func WithEntitySession(session string) EntityStoreOption {
+	return func(s *EntityStore) {
+		s.Session = session
+	}
+}
+
+// NewEntityStore creates a new EntityStore and creates its table if it does not exist.
+func NewEntityStore(ctx context.Context, options ...EntityStoreOption) (*EntityStore, error) {
+	s := &EntityStore{}
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.TableName == "" {
+		s.TableName = DefaultEntityTableName
+	}
+	if s.Session == "" {
+		s.Session = "default"
+	}
+	if s.DBAddress == "" {
+		s.DBAddress = ":memory:"
+	}
+
+	if s.DB == nil {
+		db, err := sqlitedb.Open(s.DBAddress)
+		if err != nil {
+			return nil, err
+		}
+		s.DB = db
+	}
+
+	if _, err := s.DB.ExecContext(ctx, fmt.Sprintf(DefaultEntitySchema, s.TableName)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get returns the summaries of the entities that have one.
+func (s *EntityStore) Get(ctx context.Context, entities []string) (map[string]string, error) {
+	summaries := make(map[string]string, len(entities))
+	if len(entities) == 0 {
+		return summaries, nil
+	}
+
+	query := fmt.Sprintf("SELECT entity, summary FROM %s WHERE session = ? AND entity IN (%s);",
+		s.TableName, strings.TrimSuffix(strings.Repeat("?, ", len(entities)), ", "))
+	args := make([]any, 0, len(entities)+1)
+	args = append(args, s.Session)
+	for _, entity := range entities {
+		args = append(args, entity)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entity, summary string
+		if err := rows.Scan(&entity, &summary); err != nil {
+			return nil, err
+		}
+		summaries[entity] = summary
+	}
+	return summaries, rows.Err()
+}
+
+// Set creates or replaces the summary of an entity.
+func (s *EntityStore) Set(ctx context.Context, entity string, summary string) error {
+	querytpl := []string{
+		"INSERT INTO ",
+		" (session, entity, summary, updated) VALUES (?, ?, ?, CURRENT_TIMESTAMP)" +
+			" ON CONFLICT(session, entity) DO UPDATE SET summary = excluded.summary, updated = excluded.updated;",
+	}
+	_, err := s.DB.ExecContext(ctx, strings.Join(querytpl, s.TableName), s.Session, entity, summary)
+	return err
+}
+
+// Delete removes the summary of an entity.
+func (s *EntityStore) Delete(ctx context.Context, entity string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE session = ? AND entity = ?;", s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, s.Session, entity)
+	return err
+}
+
+// Clear removes the summaries of all entities of the session.
+func (s *EntityStore) Clear(ctx context.Context) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE session = ?;", s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, s.Session)
+	return err
+}
@@ -0,0 +1,38 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/memory/sqlite3"
+)
+
+func TestSqliteEntityStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, err := sqlite3.NewEntityStore(ctx)
+	require.NoError(t, err)
+	other, err := sqlite3.NewEntityStore(ctx, sqlite3.WithEntityDB(s.DB), sqlite3.WithEntitySession("other"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Set(ctx, "Alice", "Alice lives in Paris."))
+	require.NoError(t, s.Set(ctx, "Bob", "Bob likes jazz."))
+	require.NoError(t, s.Set(ctx, "Alice", "Alice lives in Rome."))
+	require.NoError(t, other.Set(ctx, "Alice", "Another Alice."))
+
+	summaries, err := s.Get(ctx, []string{"Alice", "Bob", "Carol"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Alice": "Alice lives in Rome.", "Bob": "Bob likes jazz."}, summaries)
+
+	require.NoError(t, s.Delete(ctx, "Bob"))
+	require.NoError(t, s.Clear(ctx))
+	summaries, err = s.Get(ctx, []string{"Alice", "Bob"})
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+
+	summaries, err = other.Get(ctx, []string{"Alice"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Alice": "Another Alice."}, summaries)
+}
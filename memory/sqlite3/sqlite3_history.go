@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"strings"
 
 	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
@@ -42,11 +43,12 @@ func NewSqliteChatMessageHistory(options ...SqliteChatMessageHistoryOption) *Sql
 	return applyChatOptions(options...)
 }
 
-// Messages returns all messages stored.
+// Messages returns all messages stored. Messages stored before the table had a
+// data column are restored from their content and type.
 func (h *SqliteChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
 	querytpl := []string{
-		"SELECT content,type,created FROM ",
-		" WHERE session = ? ORDER BY created ASC LIMIT ?;",
+		"SELECT content,type,data FROM ",
+		" WHERE session = ? ORDER BY created ASC, id ASC LIMIT ?;",
 	}
 	query := strings.Join(querytpl, h.TableName)
 	res, err := h.DB.QueryContext(ctx, query, h.Session, h.Limit)
@@ -59,20 +61,23 @@ func (h *SqliteChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMes
 	var msgs []llms.ChatMessage
 	for res.Next() {
 		var content, msgtype string
-		var created interface{}
+		var data sql.NullString
 
-		if err = res.Scan(&content, &msgtype, &created); err != nil {
+		if err = res.Scan(&content, &msgtype, &data); err != nil {
 			return nil, err
 		}
 
-		switch msgtype {
-		case string(llms.ChatMessageTypeAI):
-			msgs = append(msgs, llms.AIChatMessage{Content: content})
-		case string(llms.ChatMessageTypeHuman):
-			msgs = append(msgs, llms.HumanChatMessage{Content: content})
-		case string(llms.ChatMessageTypeSystem):
-			msgs = append(msgs, llms.SystemChatMessage{Content: content})
-		default:
+		model := llms.ChatMessageModel{
+			Type: msgtype,
+			Data: llms.ChatMessageModelData{Content: content, Type: msgtype},
+		}
+		if data.Valid && data.String != "" {
+			if err := json.Unmarshal([]byte(data.String), &model); err != nil {
+				return nil, err
+			}
+		}
+		if msg := model.ToChatMessage(); msg != nil {
+			msgs = append(msgs, msg)
 		}
 	}
 
@@ -83,29 +88,62 @@ func (h *SqliteChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMes
 	return msgs, nil
 }
 
-func (h *SqliteChatMessageHistory) addMessage(ctx context.Context, text string, role llms.ChatMessageType) error {
+// Migrate adds the data column holding the full messages to a table created
+// before it existed. It is run when the history is created, and does nothing if
+// the table already has the column.
+func (h *SqliteChatMessageHistory) Migrate(ctx context.Context) error {
+	res, err := h.DB.QueryContext(ctx, "SELECT name FROM pragma_table_info(?);", h.TableName)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var name string
+		if err := res.Scan(&name); err != nil {
+			return err
+		}
+		if name == "data" {
+			return nil
+		}
+	}
+	if err := res.Err(); err != nil {
+		return err
+	}
+	res.Close()
+
+	_, err = h.DB.ExecContext(ctx, "ALTER TABLE "+h.TableName+" ADD COLUMN data TEXT;")
+	return err
+}
+
+func (h *SqliteChatMessageHistory) addMessage(ctx context.Context, message llms.ChatMessage) error {
+	data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+	if err != nil {
+		return err
+	}
+
 	querytpl := []string{
 		"INSERT INTO ",
-		" (session, content, type) VALUES (?, ?, ?);",
+		" (session, content, type, data) VALUES (?, ?, ?, ?);",
 	}
 	query := strings.Join(querytpl, h.TableName)
-	_, err := h.DB.ExecContext(ctx, query, h.Session, text, role)
+	_, err = h.DB.ExecContext(ctx, query, h.Session, message.GetContent(), message.GetType(), string(data))
 	return err
 }
 
 // AddMessage adds a message to the chat message history.
 func (h *SqliteChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
-	return h.addMessage(ctx, message.GetContent(), message.GetType())
+	return h.addMessage(ctx, message)
 }
 
 // AddAIMessage adds an AIMessage to the chat message history.
 func (h *SqliteChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
-	return h.addMessage(ctx, text, llms.ChatMessageTypeAI)
+	return h.addMessage(ctx, llms.AIChatMessage{Content: text})
 }
 
 // AddUserMessage adds a user to the chat message history.
 func (h *SqliteChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
-	return h.addMessage(ctx, text, llms.ChatMessageTypeHuman)
+	return h.addMessage(ctx, llms.HumanChatMessage{Content: text})
 }
 
 // Clear resets messages.
@@ -132,28 +170,35 @@ func (h *SqliteChatMessageHistory) SetMessages(ctx context.Context, messages []l
 	/*
 	 BEGIN TRANSACTION;
 	 DELETE FROM table WHERE session = ?;
-	 INSERT INTO table (session, content, type)
-	 VALUES (?, ?, ?), ...;
+	 INSERT INTO table (session, content, type, data)
+	 VALUES (?, ?, ?, ?), ...;
 	 COMMIT;`
 	*/
 	buf := bytes.NewBufferString("BEGIN TRANSACTION;")
 	buf.WriteString(" DELETE FROM ")
 	buf.WriteString(h.TableName)
 	buf.WriteString(" WHERE session = ?;")
-	buf.WriteString(" INSERT INTO ")
-	buf.WriteString(h.TableName)
-	buf.WriteString(" (session, content, type) VALUES ")
-
-	inputs := make([]string, len(messages))
 	values := []interface{}{h.Session}
 
-	for i, msg := range messages {
-		inputs[i] = "(?, ?, ?)"
-		values = append(values, h.Session, msg.GetContent(), string(msg.GetType()))
+	if len(messages) > 0 {
+		buf.WriteString(" INSERT INTO ")
+		buf.WriteString(h.TableName)
+		buf.WriteString(" (session, content, type, data) VALUES ")
+
+		inputs := make([]string, len(messages))
+		for i, msg := range messages {
+			data, err := json.Marshal(llms.ConvertChatMessageToModel(msg))
+			if err != nil {
+				return err
+			}
+			inputs[i] = "(?, ?, ?, ?)"
+			values = append(values, h.Session, msg.GetContent(), string(msg.GetType()), string(data))
+		}
+		buf.WriteString(strings.Join(inputs, ", "))
+		buf.WriteString(";")
 	}
 
-	buf.WriteString(strings.Join(inputs, ", "))
-	buf.WriteString("; COMMIT;")
+	buf.WriteString(" COMMIT;")
 
 	_, err := h.DB.ExecContext(ctx, buf.String(), values...)
 	return err
@@ -2,6 +2,8 @@ package sqlite3_test
 
 import (
 	"context"
+	"database/sql"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -54,3 +56,68 @@ func TestSqliteChatMessageHistory(t *testing.T) {
 		llms.HumanChatMessage{Content: "zoo"},
 	}, messages)
 }
+
+func TestSqliteChatMessageHistoryToolMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h := sqlite3.NewSqliteChatMessageHistory(sqlite3.WithContext(ctx), sqlite3.WithOverwrite())
+
+	messages := []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "what is 1+1?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "calculator", Arguments: `{"expression":"1+1"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "2"},
+		llms.FunctionChatMessage{Name: "calculator", Content: "2"},
+		llms.GenericChatMessage{Role: "moderator", Name: "bob", Content: "stay on topic"},
+	}
+	require.NoError(t, h.SetMessages(ctx, messages[:2]))
+	for _, message := range messages[2:] {
+		require.NoError(t, h.AddMessage(ctx, message))
+	}
+
+	got, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, messages, got)
+
+	require.NoError(t, h.SetMessages(ctx, nil))
+	got, err = h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestSqliteChatMessageHistoryMigrate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "history.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// the table as created before messages were stored in full.
+	_, err = db.ExecContext(ctx, `CREATE TABLE langchaingo_messages (
+		id INTEGER PRIMARY KEY,
+		name TEXT,
+		session TEXT NOT NULL,
+		content TEXT NOT NULL,
+		type TEXT NOT NULL,
+		created TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	INSERT INTO langchaingo_messages (session, content, type) VALUES ('default', 'foo', 'human');`)
+	require.NoError(t, err)
+
+	h := sqlite3.NewSqliteChatMessageHistory(sqlite3.WithContext(ctx), sqlite3.WithDB(db))
+	require.NoError(t, h.AddMessage(ctx, llms.ToolChatMessage{ID: "call_1", Content: "bar"}))
+	// migrating again does nothing.
+	require.NoError(t, h.Migrate(ctx))
+
+	messages, err := h.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "foo"},
+		llms.ToolChatMessage{ID: "call_1", Content: "bar"},
+	}, messages)
+}
@@ -0,0 +1,67 @@
+package sqlite3_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/memory/sqlite3"
+)
+
+// lastLineLLM answers with the last line of the new lines of the summary prompt.
+type lastLineLLM struct{}
+
+func (l lastLineLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (lastLineLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	_ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	prompt := messages[0].Parts[0].(llms.TextContent).Text //nolint:forcetypeassert
+	lines := strings.Split(strings.TrimSuffix(prompt, "\n\nNew summary:"), "\n")
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: lines[len(lines)-1]}}}, nil
+}
+
+func TestSqliteConversationSummary(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	address := filepath.Join(t.TempDir(), "summary.db")
+	newMemory := func() *memory.ConversationSummaryBuffer {
+		history := sqlite3.NewSqliteChatMessageHistory(sqlite3.WithContext(ctx), sqlite3.WithDBAddress(address))
+		m := memory.NewConversationSummaryBuffer(lastLineLLM{}, 3, memory.WithChatHistory(history))
+		m.CountTokens = func(text string) int { return len(strings.Fields(text)) }
+		return m
+	}
+
+	m := newMemory()
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "one"}, map[string]any{"output": "two"}))
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "three"}, map[string]any{"output": "four"}))
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history": "system: " + memory.SummaryPrefix + "Human: three\nAI: four",
+	}, result)
+
+	// the summary is persisted with the history
+	m = newMemory()
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history": "system: " + memory.SummaryPrefix + "Human: three\nAI: four",
+	}, result)
+
+	summary := memory.NewConversationSummary(lastLineLLM{}, memory.WithChatHistory(m.ChatHistory))
+	result, err = summary.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: three"}, result)
+}
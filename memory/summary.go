@@ -0,0 +1,307 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// SummaryPrefix starts the content of the system messages summaries are stored in,
+// in the chat history, and loaded in.
+const SummaryPrefix = "Summary of the conversation so far:\n"
+
+const _defaultSummaryTemplate = `Progressively summarize the lines of conversation provided, adding onto the previous summary and returning a new summary.
+
+Current summary:
+{{.summary}}
+
+New lines of conversation:
+{{.new_lines}}
+
+New summary:`
+
+// DefaultSummaryPrompt is the default prompt for updating a summary with new lines
+// of the conversation. It has the input variables "summary" and "new_lines".
+func DefaultSummaryPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultSummaryTemplate, []string{"summary", "new_lines"})
+}
+
+// ConversationSummary is a memory that keeps a running summary of the conversation
+// written by an LLM, and loads the summary instead of the messages.
+//
+// The summary is persisted with any chat history, which is only appended to: every
+// turn adds its messages followed by a system message starting with SummaryPrefix,
+// holding the updated summary. The last of these messages is the current summary.
+type ConversationSummary struct {
+	ConversationBuffer
+	LLM llms.Model
+	// Prompt updates the summary with new lines of the conversation.
+	Prompt prompts.PromptTemplate
+}
+
+// Statically assert that ConversationSummary implement the memory interface.
+var _ schema.Memory = &ConversationSummary{}
+
+// NewConversationSummary is a function for creating a new summary memory.
+func NewConversationSummary(llm llms.Model, options ...ConversationBufferOption) *ConversationSummary {
+	return &ConversationSummary{
+		ConversationBuffer: *applyBufferOptions(options...),
+		LLM:                llm,
+		Prompt:             DefaultSummaryPrompt(),
+	}
+}
+
+// MemoryVariables uses ConversationBuffer method for memory variables.
+func (m *ConversationSummary) MemoryVariables(ctx context.Context) []string {
+	return m.ConversationBuffer.MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables returns the summary of the conversation. If ReturnMessages is
+// set to true the output is a slice holding the system message of the summary, or an
+// empty slice if there is no summary yet. Otherwise, the output is the summary.
+func (m *ConversationSummary) LoadMemoryVariables(ctx context.Context, _ map[string]any) (map[string]any, error) {
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, _ := lastSummary(messages)
+
+	if m.ReturnMessages {
+		result := []llms.ChatMessage{}
+		if summary != "" {
+			result = append(result, summaryMessage(summary))
+		}
+		return map[string]any{m.MemoryKey: result}, nil
+	}
+
+	return map[string]any{m.MemoryKey: summary}, nil
+}
+
+// SaveContext updates the summary with the messages of the model run, and adds the
+// messages and the summary to the chat history.
+func (m *ConversationSummary) SaveContext(
+	ctx context.Context,
+	inputValues map[string]any,
+	outputValues map[string]any,
+) error {
+	newMessages, err := m.contextMessages(inputValues, outputValues)
+	if err != nil {
+		return err
+	}
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+	summary, _ := lastSummary(messages)
+
+	summary, err = m.summarize(ctx, summary, newMessages)
+	if err != nil {
+		return err
+	}
+	return addMessages(ctx, m.ChatHistory, append(newMessages, summaryMessage(summary)))
+}
+
+// Clear removes the summary and the messages from the chat history.
+func (m *ConversationSummary) Clear(ctx context.Context) error {
+	return m.ConversationBuffer.Clear(ctx)
+}
+
+// contextMessages returns the user and AI messages of a model run.
+func (m *ConversationSummary) contextMessages(
+	inputValues map[string]any,
+	outputValues map[string]any,
+) ([]llms.ChatMessage, error) {
+	input, err := GetInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	output, err := GetInputValue(outputValues, m.OutputKey)
+	if err != nil {
+		return nil, err
+	}
+	return []llms.ChatMessage{
+		llms.HumanChatMessage{Content: input},
+		llms.AIChatMessage{Content: output},
+	}, nil
+}
+
+// summarize returns the summary updated with the messages.
+func (m *ConversationSummary) summarize(
+	ctx context.Context,
+	summary string,
+	messages []llms.ChatMessage,
+) (string, error) {
+	if len(messages) == 0 {
+		return summary, nil
+	}
+
+	newLines, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := m.Prompt.Format(map[string]any{
+		"summary":   summary,
+		"new_lines": newLines,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	newSummary, err := llms.GenerateFromSinglePrompt(ctx, m.LLM, prompt)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(newSummary), nil
+}
+
+// ConversationSummaryBuffer is a memory that keeps the recent messages of the
+// conversation, and folds the oldest messages into a summary written by an LLM once
+// the messages are longer than MaxTokenLimit.
+//
+// The summary is persisted with any chat history, which is only appended to: the
+// messages of every turn are added, and when messages are folded, a system message
+// starting with SummaryPrefix holding the updated summary is added, followed again
+// by the earlier messages that are kept. The recent messages are those following
+// the last summary.
+type ConversationSummaryBuffer struct {
+	ConversationSummary
+	MaxTokenLimit int
+	// CountTokens returns the number of tokens of a text. The default counts the
+	// tokens with the tokenizer llms.GetTokenizer returns for the default model.
+	CountTokens func(text string) int
+}
+
+// Statically assert that ConversationSummaryBuffer implement the memory interface.
+var _ schema.Memory = &ConversationSummaryBuffer{}
+
+// NewConversationSummaryBuffer is a function for creating a new summary buffer memory.
+func NewConversationSummaryBuffer(
+	llm llms.Model,
+	maxTokenLimit int,
+	options ...ConversationBufferOption,
+) *ConversationSummaryBuffer {
+	return &ConversationSummaryBuffer{
+		ConversationSummary: *NewConversationSummary(llm, options...),
+		MaxTokenLimit:       maxTokenLimit,
+		CountTokens: func(text string) int {
+			return llms.GetTokenizer("").CountTokens(text)
+		},
+	}
+}
+
+// LoadMemoryVariables returns the system message of the summary followed by the
+// recent messages, as a slice if ReturnMessages is set to true and as a buffer
+// string otherwise, like ConversationBuffer does.
+func (m *ConversationSummaryBuffer) LoadMemoryVariables(
+	ctx context.Context, _ map[string]any,
+) (map[string]any, error) {
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, recent := lastSummary(messages)
+
+	loaded := make([]llms.ChatMessage, 0, len(recent)+1)
+	if summary != "" {
+		loaded = append(loaded, summaryMessage(summary))
+	}
+	loaded = append(loaded, recent...)
+
+	if m.ReturnMessages {
+		return map[string]any{m.MemoryKey: loaded}, nil
+	}
+
+	bufferString, err := llms.GetBufferString(loaded, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{m.MemoryKey: bufferString}, nil
+}
+
+// SaveContext adds the messages of the model run to the chat history and, while the
+// recent messages are longer than MaxTokenLimit, folds the oldest ones into the summary.
+func (m *ConversationSummaryBuffer) SaveContext(
+	ctx context.Context,
+	inputValues map[string]any,
+	outputValues map[string]any,
+) error {
+	newMessages, err := m.contextMessages(inputValues, outputValues)
+	if err != nil {
+		return err
+	}
+
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+	summary, recent := lastSummary(messages)
+	numStored := len(recent)
+	recent = append(recent, newMessages...)
+
+	pruned := 0
+	for pruned < len(recent) {
+		length, err := m.numTokens(recent[pruned:])
+		if err != nil {
+			return err
+		}
+		if length <= m.MaxTokenLimit {
+			break
+		}
+		pruned++
+	}
+	if pruned == 0 {
+		return addMessages(ctx, m.ChatHistory, newMessages)
+	}
+
+	summary, err = m.summarize(ctx, summary, recent[:pruned])
+	if err != nil {
+		return err
+	}
+	// the new messages folded are added ahead of the summary, and the kept messages,
+	// including stored ones, after it
+	added := make([]llms.ChatMessage, 0, len(newMessages)+1)
+	if pruned > numStored {
+		added = append(added, newMessages[:pruned-numStored]...)
+	}
+	added = append(added, summaryMessage(summary))
+	added = append(added, recent[pruned:]...)
+	return addMessages(ctx, m.ChatHistory, added)
+}
+
+func (m *ConversationSummaryBuffer) numTokens(messages []llms.ChatMessage) (int, error) {
+	bufferString, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return 0, err
+	}
+	return m.CountTokens(bufferString), nil
+}
+
+// lastSummary returns the summary of the last summary message of the messages, if
+// any, and the messages following it.
+func lastSummary(messages []llms.ChatMessage) (string, []llms.ChatMessage) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].GetType() == llms.ChatMessageTypeSystem &&
+			strings.HasPrefix(messages[i].GetContent(), SummaryPrefix) {
+			return strings.TrimPrefix(messages[i].GetContent(), SummaryPrefix), messages[i+1:]
+		}
+	}
+	return "", messages
+}
+
+func summaryMessage(summary string) llms.ChatMessage {
+	return llms.SystemChatMessage{Content: SummaryPrefix + summary}
+}
+
+// addMessages adds the messages to the chat history in order.
+func addMessages(ctx context.Context, history schema.ChatMessageHistory, messages []llms.ChatMessage) error {
+	for _, message := range messages {
+		if err := history.AddMessage(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// summaryLLM summarizes by appending the new lines of the prompt to the summary.
+type summaryLLM struct {
+	calls int
+}
+
+func (l *summaryLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l *summaryLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	_ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	l.calls++
+	prompt := messages[0].Parts[0].(llms.TextContent).Text //nolint:forcetypeassert
+
+	summary := between(prompt, "Current summary:\n", "\n\nNew lines")
+	newLines := between(prompt, "New lines of conversation:\n", "\n\nNew summary:")
+	newSummary := strings.TrimSpace(summary + " " + strings.ReplaceAll(newLines, "\n", " "))
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: newSummary}}}, nil
+}
+
+func between(s, start, end string) string {
+	s = s[strings.Index(s, start)+len(start):]
+	return s[:strings.Index(s, end)]
+}
+
+func TestConversationSummary(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	history := NewChatMessageHistory()
+	llm := &summaryLLM{}
+	m := NewConversationSummary(llm, WithChatHistory(history))
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "hi"}, map[string]any{"output": "hello"}))
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "bye"}, map[string]any{"output": "ciao"}))
+	assert.Equal(t, 2, llm.calls)
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: hi AI: hello Human: bye AI: ciao"}, result)
+
+	// the messages and the summaries are added to the history
+	messages, err := history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "hi"},
+		llms.AIChatMessage{Content: "hello"},
+		llms.SystemChatMessage{Content: SummaryPrefix + "Human: hi AI: hello"},
+		llms.HumanChatMessage{Content: "bye"},
+		llms.AIChatMessage{Content: "ciao"},
+		llms.SystemChatMessage{Content: SummaryPrefix + "Human: hi AI: hello Human: bye AI: ciao"},
+	}, messages)
+
+	// a new memory on the same history continues the summary
+	m = NewConversationSummary(llm, WithChatHistory(history), WithReturnMessages(true))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{messages[5]}}, result)
+
+	require.NoError(t, m.Clear(ctx))
+	messages, err = history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestConversationSummaryBuffer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	history := NewChatMessageHistory()
+	llm := &summaryLLM{}
+	m := NewConversationSummaryBuffer(llm, 6, WithChatHistory(history))
+	m.CountTokens = func(text string) int { return len(strings.Fields(text)) }
+
+	// "Human: one AI: two" is 4 tokens, within the limit
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "one"}, map[string]any{"output": "two"}))
+	assert.Equal(t, 0, llm.calls)
+
+	// "Human: one" is folded into the summary to get back within the limit
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "three"}, map[string]any{"output": "four"}))
+	assert.Equal(t, 1, llm.calls)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history": "system: " + SummaryPrefix + "Human: one\nAI: two\nHuman: three\nAI: four",
+	}, result)
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "five"}, map[string]any{"output": "six"}))
+
+	// a new memory on the same history loads the summary and the recent messages
+	m = NewConversationSummaryBuffer(llm, 6, WithChatHistory(history), WithReturnMessages(true))
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{
+		llms.SystemChatMessage{Content: SummaryPrefix + "Human: one AI: two Human: three"},
+		llms.AIChatMessage{Content: "four"},
+		llms.HumanChatMessage{Content: "five"},
+		llms.AIChatMessage{Content: "six"},
+	}}, result)
+
+	// the history is only appended to, so kept messages are added again after the
+	// summaries
+	messages, err := history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "one"},
+		llms.AIChatMessage{Content: "two"},
+		llms.SystemChatMessage{Content: SummaryPrefix + "Human: one"},
+		llms.AIChatMessage{Content: "two"},
+		llms.HumanChatMessage{Content: "three"},
+		llms.AIChatMessage{Content: "four"},
+		llms.SystemChatMessage{Content: SummaryPrefix + "Human: one AI: two Human: three"},
+		llms.AIChatMessage{Content: "four"},
+		llms.HumanChatMessage{Content: "five"},
+		llms.AIChatMessage{Content: "six"},
+	}, messages)
+}
@@ -12,6 +12,9 @@ type ConversationTokenBuffer struct {
 	ConversationBuffer
 	LLM           llms.Model
 	MaxTokenLimit int
+	// Tokenizer counts the tokens of the messages. If nil, the tokenizer
+	// llms.GetTokenizer returns for the default model is used.
+	Tokenizer llms.Tokenizer
 }
 
 // Statically assert that ConversationTokenBuffer implement the memory interface.
@@ -105,5 +108,9 @@ func (tb *ConversationTokenBuffer) getNumTokensFromMessages(ctx context.Context)
 		return 0, err
 	}
 
-	return llms.CountTokens("", bufferString), nil
+	tokenizer := tb.Tokenizer
+	if tokenizer == nil {
+		tokenizer = llms.GetTokenizer("")
+	}
+	return tokenizer.CountTokens(bufferString), nil
 }
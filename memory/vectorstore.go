@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// SessionIDKey is the metadata key of the session of the exchanges saved by
+	// VectorStoreMemory.
+	SessionIDKey = "session_id"
+	// TimestampKey is the metadata key of the time, in RFC 3339 format, of the
+	// exchanges saved by VectorStoreMemory.
+	TimestampKey = "timestamp"
+
+	_defaultVectorStoreNumDocuments = 4
+	_defaultVectorStoreMemoryKey    = "relevant_history"
+)
+
+// VectorStoreMemory is a long-term memory that saves every exchange of the
+// conversation as a document in a vector store, and loads the past exchanges most
+// relevant to the current input. It can be combined with a buffer memory for the
+// most recent turns using Combined.
+type VectorStoreMemory struct {
+	Store vectorstores.VectorStore
+	// NumDocuments is the number of past exchanges loaded.
+	NumDocuments int
+	// SessionID is saved in the metadata of the exchanges.
+	SessionID string
+	// SearchOptions are the options of the vector store searches, such as filters
+	// limiting the exchanges loaded to the session.
+	SearchOptions []vectorstores.Option
+
+	ReturnDocs  bool
+	InputKey    string
+	OutputKey   string
+	HumanPrefix string
+	AIPrefix    string
+	MemoryKey   string
+
+	now func() time.Time
+}
+
+// Statically assert that VectorStoreMemory implement the memory interface.
+var _ schema.Memory = &VectorStoreMemory{}
+
+// VectorStoreMemoryOption is a function for creating a new vector store memory
+// with other than the default values.
+type VectorStoreMemoryOption func(m *VectorStoreMemory)
+
+// WithNumDocuments is an option for specifying the number of past exchanges loaded.
+func WithNumDocuments(n int) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.NumDocuments = n
+	}
+}
+
+// WithSessionID is an option for specifying the session saved with the exchanges.
+func WithSessionID(sessionID string) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.SessionID = sessionID
+	}
+}
+
+// WithSearchOptions is an option for specifying the options of the vector store searches.
+func WithSearchOptions(options ...vectorstores.Option) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.SearchOptions = options
+	}
+}
+
+// WithReturnDocs is an option for specifying whether the past exchanges are returned
+// as documents instead of text.
+func WithReturnDocs(returnDocs bool) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.ReturnDocs = returnDocs
+	}
+}
+
+// WithVectorStoreKeys is an option for specifying the input, output and memory keys.
+// The default memory key is "relevant_history", so that the memory can be combined
+// with a buffer memory loading "history".
+func WithVectorStoreKeys(inputKey, outputKey, memoryKey string) VectorStoreMemoryOption {
+	return func(m *VectorStoreMemory) {
+		m.InputKey = inputKey
+		m.OutputKey = outputKey
+		m.MemoryKey = memoryKey
+	}
+}
+
+// NewVectorStoreMemory is a function for creating a new vector store memory.
+func NewVectorStoreMemory(store vectorstores.VectorStore, options ...VectorStoreMemoryOption) *VectorStoreMemory {
+	m := &VectorStoreMemory{
+		Store:        store,
+		NumDocuments: _defaultVectorStoreNumDocuments,
+		HumanPrefix:  "Human",
+		AIPrefix:     "AI",
+		MemoryKey:    _defaultVectorStoreMemoryKey,
+		now:          time.Now,
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+// MemoryVariables gets the memory key the vector store memory will load dynamically.
+func (m *VectorStoreMemory) MemoryVariables(context.Context) []string {
+	return []string{m.MemoryKey}
+}
+
+// GetMemoryKey getter for memory key.
+func (m *VectorStoreMemory) GetMemoryKey(context.Context) string {
+	return m.MemoryKey
+}
+
+// LoadMemoryVariables returns the past exchanges most relevant to the input, in a
+// map with the key specified in the MemoryKey field. If ReturnDocs is set to true the
+// output is a slice of schema.Document. Otherwise, the output is the text of the
+// exchanges separated by blank lines.
+func (m *VectorStoreMemory) LoadMemoryVariables(
+	ctx context.Context,
+	inputs map[string]any,
+) (map[string]any, error) {
+	var docs []schema.Document
+	query, err := GetInputValue(inputs, m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	if query != "" {
+		docs, err = m.Store.SimilaritySearch(ctx, query, m.NumDocuments, m.SearchOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.ReturnDocs {
+		if docs == nil {
+			docs = []schema.Document{}
+		}
+		return map[string]any{m.MemoryKey: docs}, nil
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	return map[string]any{m.MemoryKey: strings.Join(texts, "\n\n")}, nil
+}
+
+// SaveContext saves the input and output values of the model run as a document, with
+// the session and the time of the exchange in its metadata.
+func (m *VectorStoreMemory) SaveContext(
+	ctx context.Context,
+	inputValues map[string]any,
+	outputValues map[string]any,
+) error {
+	input, err := GetInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+	output, err := GetInputValue(outputValues, m.OutputKey)
+	if err != nil {
+		return err
+	}
+
+	doc := schema.Document{
+		PageContent: fmt.Sprintf("%s: %s\n%s: %s", m.HumanPrefix, input, m.AIPrefix, output),
+		Metadata: map[string]any{
+			TimestampKey: m.now().UTC().Format(time.RFC3339Nano),
+		},
+	}
+	if m.SessionID != "" {
+		doc.Metadata[SessionIDKey] = m.SessionID
+	}
+
+	_, err = m.Store.AddDocuments(ctx, []schema.Document{doc})
+	return err
+}
+
+// Clear does nothing, as vector stores can not be cleared. Past exchanges stay in
+// the vector store.
+func (m *VectorStoreMemory) Clear(context.Context) error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// wordStore is a vector store returning the documents sharing the most words with
+// the query.
+type wordStore struct {
+	docs []schema.Document
+}
+
+func (s *wordStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	s.docs = append(s.docs, docs...)
+	return make([]string, len(docs)), nil
+}
+
+func (s *wordStore) SimilaritySearch(
+	_ context.Context,
+	query string,
+	numDocuments int,
+	_ ...vectorstores.Option,
+) ([]schema.Document, error) {
+	var found []schema.Document
+	for _, word := range strings.Fields(query) {
+		for _, doc := range s.docs {
+			if strings.Contains(doc.PageContent, word) && len(found) < numDocuments {
+				found = append(found, doc)
+			}
+		}
+	}
+	return found, nil
+}
+
+func TestVectorStoreMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := &wordStore{}
+	m := NewVectorStoreMemory(store, WithSessionID("session"), WithNumDocuments(1))
+	m.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, m.SaveContext(ctx,
+		map[string]any{"input": "my favourite food is pizza"}, map[string]any{"output": "noted"}))
+	require.NoError(t, m.SaveContext(ctx,
+		map[string]any{"input": "my favourite sport is tennis"}, map[string]any{"output": "nice"}))
+	assert.Equal(t, map[string]any{
+		SessionIDKey: "session",
+		TimestampKey: "2024-05-01T12:00:00Z",
+	}, store.docs[0].Metadata)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "tennis"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"relevant_history": "Human: my favourite sport is tennis\nAI: nice"}, result)
+
+	m.ReturnDocs = true
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "pizza"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"relevant_history": store.docs[:1]}, result)
+
+	_, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "pizza", "other": "tennis"})
+	require.ErrorIs(t, err, ErrInvalidInputValues)
+}
+
+func TestCombinedMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	longTerm := NewVectorStoreMemory(&wordStore{})
+	shortTerm := NewConversationWindowBuffer(1, WithInputKey("input"), WithOutputKey("output"))
+	m, err := NewCombined(ctx, shortTerm, longTerm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"history", "relevant_history"}, m.MemoryVariables(ctx))
+
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "I live in Paris"}, map[string]any{"output": "ok"}))
+	require.NoError(t, m.SaveContext(ctx, map[string]any{"input": "I like jazz"}, map[string]any{"output": "cool"}))
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "Paris"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"history":          "Human: I like jazz\nAI: cool",
+		"relevant_history": "Human: I live in Paris\nAI: ok",
+	}, result)
+
+	_, err = NewCombined(ctx, shortTerm, NewConversationBuffer())
+	require.ErrorIs(t, err, ErrDuplicateMemoryVariable)
+}
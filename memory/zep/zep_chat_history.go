@@ -2,6 +2,7 @@ package zep
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 
@@ -31,17 +32,41 @@ func NewZepChatMessageHistory(zep *zepClient.Client, sessionID string, options .
 	return messageHistory
 }
 
+// MessageMetadataKey is the key of the zep message metadata the full chat message is
+// stored under, so tool calls, tool call ids and names survive a round-trip.
+const MessageMetadataKey = "langchaingo_message"
+
 func (h *ChatMessageHistory) messagesFromZepMessages(zepMessages []*zep.Message) []llms.ChatMessage {
 	var chatMessages []llms.ChatMessage
 	for _, zepMessage := range zepMessages {
-		switch *zepMessage.RoleType { // nolint We do not store other message types in zep memory
+		content := ""
+		if zepMessage.Content != nil {
+			content = *zepMessage.Content
+		}
+		if message, ok := messageFromMetadata(zepMessage.Metadata, content); ok {
+			chatMessages = append(chatMessages, message)
+			continue
+		}
+		if zepMessage.RoleType == nil {
+			continue
+		}
+		switch *zepMessage.RoleType {
 		case zep.RoleTypeUserRole:
-			chatMessages = append(chatMessages, llms.HumanChatMessage{Content: *zepMessage.Content})
+			chatMessages = append(chatMessages, llms.HumanChatMessage{Content: content})
 		case zep.RoleTypeAssistantRole:
-			chatMessages = append(chatMessages, llms.AIChatMessage{Content: *zepMessage.Content})
+			chatMessages = append(chatMessages, llms.AIChatMessage{Content: content})
+		case zep.RoleTypeSystemRole:
+			chatMessages = append(chatMessages, llms.SystemChatMessage{Content: content})
 		case zep.RoleTypeToolRole:
+			chatMessages = append(chatMessages, llms.ToolChatMessage{Content: content})
 		case zep.RoleTypeFunctionRole:
-			chatMessages = append(chatMessages, llms.ToolChatMessage{Content: *zepMessage.Content})
+			chatMessages = append(chatMessages, llms.FunctionChatMessage{Content: content})
+		case zep.RoleTypeNoRole:
+			role := ""
+			if zepMessage.Role != nil {
+				role = *zepMessage.Role
+			}
+			chatMessages = append(chatMessages, llms.GenericChatMessage{Role: role, Content: content})
 		default:
 			log.Print(fmt.Errorf("unknown role: %s", *zepMessage.RoleType))
 			continue
@@ -56,7 +81,7 @@ func (h *ChatMessageHistory) messagesToZepMessages(messages []llms.ChatMessage)
 		zepMessage := zep.Message{
 			Content: zep.String(m.GetContent()),
 		}
-		switch m.GetType() { // nolint We only expect to bring these three types into chat history
+		switch m.GetType() {
 		case llms.ChatMessageTypeHuman:
 			zepMessage.RoleType = zep.RoleTypeUserRole.Ptr()
 			if h.HumanPrefix != "" {
@@ -67,19 +92,65 @@ func (h *ChatMessageHistory) messagesToZepMessages(messages []llms.ChatMessage)
 			if h.AIPrefix != "" {
 				zepMessage.Role = zep.String(h.AIPrefix)
 			}
+		case llms.ChatMessageTypeSystem:
+			zepMessage.RoleType = zep.RoleTypeSystemRole.Ptr()
 		case llms.ChatMessageTypeFunction:
 			zepMessage.RoleType = zep.RoleTypeFunctionRole.Ptr()
 		case llms.ChatMessageTypeTool:
 			zepMessage.RoleType = zep.RoleTypeToolRole.Ptr()
+		case llms.ChatMessageTypeGeneric:
+			zepMessage.RoleType = zep.RoleTypeNoRole.Ptr()
+			if generic, ok := m.(llms.GenericChatMessage); ok && generic.Role != "" {
+				zepMessage.Role = zep.String(generic.Role)
+			}
 		default:
-			log.Print(fmt.Errorf("unknown role: %s", *zepMessage.RoleType))
+			log.Print(fmt.Errorf("unknown role: %s", m.GetType()))
 			continue
 		}
+		metadata, err := messageMetadata(m)
+		if err != nil {
+			log.Print(fmt.Errorf("storing the full message failed: %w", err))
+		} else {
+			zepMessage.Metadata = metadata
+		}
 		zepMessages = append(zepMessages, &zepMessage)
 	}
 	return zepMessages
 }
 
+// messageMetadata returns the metadata storing the full chat message.
+func messageMetadata(message llms.ChatMessage) (map[string]interface{}, error) {
+	data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+	if err != nil {
+		return nil, err
+	}
+	var model map[string]interface{}
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{MessageMetadataKey: model}, nil
+}
+
+// messageFromMetadata returns the chat message stored in the metadata of a zep
+// message, if any. The content of the zep message is used as the content.
+func messageFromMetadata(metadata map[string]interface{}, content string) (llms.ChatMessage, bool) {
+	stored, ok := metadata[MessageMetadataKey]
+	if !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, false
+	}
+	var model llms.ChatMessageModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, false
+	}
+	model.Data.Content = content
+	message := model.ToChatMessage()
+	return message, message != nil
+}
+
 // Messages returns all messages stored.
 func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
 	memory, err := h.ZepClient.Memory.Get(ctx, h.SessionID, &zep.MemoryGetRequest{
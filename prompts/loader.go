@@ -0,0 +1,325 @@
+package prompts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"sigs.k8s.io/yaml"
+)
+
+const _maxIncludeDepth = 10
+
+var (
+	// ErrInvalidPromptFile is returned when a prompt file can not be loaded.
+	ErrInvalidPromptFile = errors.New("invalid prompt file")
+	// ErrIncludeCycle is returned when the includes of a template are nested too deeply,
+	// which usually means a template includes itself.
+	ErrIncludeCycle = errors.New("include cycle in template")
+)
+
+// includePatterns are the patterns of the include statements that are expanded when
+// loading a template, per template format. The first group is the included path. Go
+// templates use a dedicated include statement, so that {{template "name"}} still
+// executes the templates declared with {{define "name"}}.
+var includePatterns = map[TemplateFormat]*regexp.Regexp{ //nolint:gochecknoglobals
+	TemplateFormatGoTemplate: regexp.MustCompile(`{{-?\s*include\s+"([^"]+)"\s*-?}}`),
+	TemplateFormatJinja2:     regexp.MustCompile(`{%-?\s*include\s+["']([^"']+)["']\s*-?%}`),
+}
+
+// PromptFile is the definition of a prompt as stored in a YAML or JSON file.
+//
+// A file defines a PromptTemplate with template, or a ChatPromptTemplate with
+// messages. Templates can be given inline or with a path relative to the file. Go
+// templates can include other files with {{include "path"}} and jinja2 templates
+// with {% include "path" %}; the included file is inserted in place of the statement
+// when the prompt is loaded.
+type PromptFile struct {
+	Template         string         `json:"template,omitempty"`
+	TemplatePath     string         `json:"template_path,omitempty"`
+	TemplateFormat   TemplateFormat `json:"template_format,omitempty"`
+	InputVariables   []string       `json:"input_variables,omitempty"`
+	PartialVariables map[string]any `json:"partial_variables,omitempty"`
+	Messages         []MessageFile  `json:"messages,omitempty"`
+}
+
+// MessageFile is the definition of a message of a chat prompt file. Role is one of
+// "system", "human", "ai" or "placeholder". Any other role creates a generic message
+// with that role. Placeholders insert the messages of the input named VariableName.
+type MessageFile struct {
+	Role           string   `json:"role"`
+	Template       string   `json:"template,omitempty"`
+	TemplatePath   string   `json:"template_path,omitempty"`
+	InputVariables []string `json:"input_variables,omitempty"`
+	VariableName   string   `json:"variable_name,omitempty"`
+}
+
+// LoadPrompt loads a prompt from a YAML or JSON file in fsys, such as an os.DirFS or
+// an embed.FS. It returns a PromptTemplate or a ChatPromptTemplate depending on
+// whether the file defines messages.
+func LoadPrompt(fsys fs.FS, name string) (FormatPrompter, error) { //nolint:ireturn
+	l := &promptLoader{fsys: fsys}
+	return l.load(name)
+}
+
+// LoadPromptTemplate loads a PromptTemplate from a YAML or JSON file in fsys.
+func LoadPromptTemplate(fsys fs.FS, name string) (PromptTemplate, error) {
+	p, err := LoadPrompt(fsys, name)
+	if err != nil {
+		return PromptTemplate{}, err
+	}
+	prompt, ok := p.(PromptTemplate)
+	if !ok {
+		return PromptTemplate{}, fmt.Errorf("%w: %s defines a chat prompt", ErrInvalidPromptFile, name)
+	}
+	return prompt, nil
+}
+
+// LoadChatPromptTemplate loads a ChatPromptTemplate from a YAML or JSON file in fsys.
+func LoadChatPromptTemplate(fsys fs.FS, name string) (ChatPromptTemplate, error) {
+	p, err := LoadPrompt(fsys, name)
+	if err != nil {
+		return ChatPromptTemplate{}, err
+	}
+	prompt, ok := p.(ChatPromptTemplate)
+	if !ok {
+		return ChatPromptTemplate{}, fmt.Errorf("%w: %s does not define messages", ErrInvalidPromptFile, name)
+	}
+	return prompt, nil
+}
+
+// promptLoader loads a prompt and records the files it was loaded from.
+type promptLoader struct {
+	fsys  fs.FS
+	files []string
+}
+
+func (l *promptLoader) readFile(name string) ([]byte, error) {
+	l.files = append(l.files, name)
+	return fs.ReadFile(l.fsys, name)
+}
+
+func (l *promptLoader) load(name string) (FormatPrompter, error) { //nolint:ireturn
+	data, err := l.readFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPromptFile, name, err)
+		}
+	case ".json":
+	default:
+		return nil, fmt.Errorf("%w: %s: unsupported extension", ErrInvalidPromptFile, name)
+	}
+
+	var file PromptFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPromptFile, name, err)
+	}
+	if file.TemplateFormat == "" {
+		file.TemplateFormat = TemplateFormatGoTemplate
+	}
+	if _, ok := defaultFormatterMapping[file.TemplateFormat]; !ok {
+		return nil, newInvalidTemplateError(file.TemplateFormat)
+	}
+
+	dir := path.Dir(name)
+	if len(file.Messages) == 0 {
+		template, err := l.template(dir, file.Template, file.TemplatePath, file.TemplateFormat)
+		if err != nil {
+			return nil, err
+		}
+		return PromptTemplate{
+			Template:         template,
+			InputVariables:   file.InputVariables,
+			TemplateFormat:   file.TemplateFormat,
+			PartialVariables: file.PartialVariables,
+		}, nil
+	}
+
+	messages := make([]MessageFormatter, 0, len(file.Messages))
+	for _, m := range file.Messages {
+		message, err := l.message(dir, m, file.TemplateFormat)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return ChatPromptTemplate{
+		Messages:         messages,
+		PartialVariables: file.PartialVariables,
+	}, nil
+}
+
+func (l *promptLoader) message(dir string, m MessageFile, format TemplateFormat) (MessageFormatter, error) { //nolint:ireturn,lll
+	if m.Role == "placeholder" {
+		return MessagesPlaceholder{VariableName: m.VariableName}, nil
+	}
+
+	template, err := l.template(dir, m.Template, m.TemplatePath, format)
+	if err != nil {
+		return nil, err
+	}
+	prompt := PromptTemplate{
+		Template:       template,
+		InputVariables: m.InputVariables,
+		TemplateFormat: format,
+	}
+
+	switch llms.ChatMessageType(m.Role) {
+	case llms.ChatMessageTypeSystem:
+		return SystemMessagePromptTemplate{Prompt: prompt}, nil
+	case llms.ChatMessageTypeHuman:
+		return HumanMessagePromptTemplate{Prompt: prompt}, nil
+	case llms.ChatMessageTypeAI:
+		return AIMessagePromptTemplate{Prompt: prompt}, nil
+	case "":
+		return nil, fmt.Errorf("%w: message without role", ErrInvalidPromptFile)
+	default:
+		return GenericMessagePromptTemplate{Prompt: prompt, Role: m.Role}, nil
+	}
+}
+
+// template returns the inline template, or the template read from templatePath, with
+// its includes expanded.
+func (l *promptLoader) template(dir, template, templatePath string, format TemplateFormat) (string, error) {
+	if templatePath != "" {
+		name := path.Join(dir, templatePath)
+		data, err := l.readFile(name)
+		if err != nil {
+			return "", err
+		}
+		return l.expandIncludes(path.Dir(name), string(data), format, 0)
+	}
+
+	return l.expandIncludes(dir, template, format, 0)
+}
+
+func (l *promptLoader) expandIncludes(dir, template string, format TemplateFormat, depth int) (string, error) {
+	pattern, ok := includePatterns[format]
+	if !ok {
+		return template, nil
+	}
+	if depth > _maxIncludeDepth {
+		return "", ErrIncludeCycle
+	}
+
+	var expandErr error
+	expanded := pattern.ReplaceAllStringFunc(template, func(statement string) string {
+		if expandErr != nil {
+			return statement
+		}
+
+		name := path.Join(dir, pattern.FindStringSubmatch(statement)[1])
+		data, err := l.readFile(name)
+		if err != nil {
+			expandErr = err
+			return statement
+		}
+
+		included, err := l.expandIncludes(path.Dir(name), string(data), format, depth+1)
+		if err != nil {
+			expandErr = err
+			return statement
+		}
+		return included
+	})
+
+	return expanded, expandErr
+}
+
+// ReloadingPrompt is a prompt loaded from a file that is loaded again when the file,
+// or a file it includes, has been modified since it was last loaded. It is meant for
+// editing prompts without restarting during development. File systems that do not
+// report modification times, such as embed.FS, are loaded only once.
+type ReloadingPrompt struct {
+	fsys fs.FS
+	name string
+
+	mu      sync.Mutex
+	prompt  FormatPrompter
+	modTime map[string]time.Time
+}
+
+var _ FormatPrompter = &ReloadingPrompt{}
+
+// NewReloadingPrompt loads the prompt in the file name of fsys and returns a prompt
+// that reloads it whenever it is modified.
+func NewReloadingPrompt(fsys fs.FS, name string) (*ReloadingPrompt, error) {
+	p := &ReloadingPrompt{fsys: fsys, name: name}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FormatPrompt reloads the prompt if it was modified and formats it.
+func (p *ReloadingPrompt) FormatPrompt(values map[string]any) (llms.PromptValue, error) { //nolint:ireturn
+	prompt, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	return prompt.FormatPrompt(values)
+}
+
+// GetInputVariables reloads the prompt if it was modified and returns its input variables.
+func (p *ReloadingPrompt) GetInputVariables() []string {
+	prompt, err := p.current()
+	if err != nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.prompt.GetInputVariables()
+	}
+	return prompt.GetInputVariables()
+}
+
+func (p *ReloadingPrompt) current() (FormatPrompter, error) { //nolint:ireturn
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for name, modTime := range p.modTime {
+		info, err := fs.Stat(p.fsys, name)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			if err := p.reload(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	return p.prompt, nil
+}
+
+func (p *ReloadingPrompt) reload() error {
+	l := &promptLoader{fsys: p.fsys}
+	prompt, err := l.load(p.name)
+	if err != nil {
+		return err
+	}
+
+	modTime := make(map[string]time.Time, len(l.files))
+	for _, name := range l.files {
+		info, err := fs.Stat(p.fsys, name)
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().IsZero() {
+			modTime[name] = info.ModTime()
+		}
+	}
+
+	p.prompt = prompt
+	p.modTime = modTime
+	return nil
+}
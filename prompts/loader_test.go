@@ -0,0 +1,110 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestLoadPromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	p, err := LoadPromptTemplate(os.DirFS("testdata"), "qa.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"question"}, p.GetInputVariables())
+
+	result, err := p.Format(map[string]any{"question": "why?"})
+	require.NoError(t, err)
+	require.Equal(t, "Answer in a friendly tone.\nQuestion: why?\n", result)
+
+	_, err = LoadChatPromptTemplate(os.DirFS("testdata"), "qa.yaml")
+	require.ErrorIs(t, err, ErrInvalidPromptFile)
+}
+
+func TestLoadPromptTemplateDefine(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"prompt.yaml":   {Data: []byte(`template_path: prompt.tmpl`)},
+		"prompt.tmpl":   {Data: []byte(`{{include "greeting.tmpl"}}{{template "greeting" .}}`)},
+		"greeting.tmpl": {Data: []byte(`{{define "greeting"}}hello {{.name}}{{end}}`)},
+	}
+
+	p, err := LoadPromptTemplate(fsys, "prompt.yaml")
+	require.NoError(t, err)
+
+	result, err := p.Format(map[string]any{"name": "foo"})
+	require.NoError(t, err)
+	require.Equal(t, "hello foo", result)
+}
+
+func TestLoadChatPromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	p, err := LoadChatPromptTemplate(os.DirFS("testdata"), "chat.json")
+	require.NoError(t, err)
+
+	messages, err := p.FormatMessages(map[string]any{
+		"question": "who are you?",
+		"history":  []llms.ChatMessage{llms.AIChatMessage{Content: "hi"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "You are Bob, a helpful assistant."},
+		llms.AIChatMessage{Content: "hi"},
+		llms.HumanChatMessage{Content: "who are you?"},
+	}, messages)
+}
+
+func TestLoadPromptErrors(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"cycle.yaml":  {Data: []byte(`template: '{{include "cycle.tmpl"}}'`)},
+		"cycle.tmpl":  {Data: []byte(`{{include "cycle.tmpl"}}`)},
+		"format.json": {Data: []byte(`{"template": "x", "template_format": "mustache"}`)},
+		"prompt.txt":  {Data: []byte(`hello`)},
+		"role.yaml":   {Data: []byte("messages:\n  - template: hello\n")},
+	}
+
+	_, err := LoadPrompt(fsys, "cycle.yaml")
+	require.ErrorIs(t, err, ErrIncludeCycle)
+	_, err = LoadPrompt(fsys, "format.json")
+	require.ErrorIs(t, err, ErrInvalidTemplateFormat)
+	_, err = LoadPrompt(fsys, "prompt.txt")
+	require.ErrorIs(t, err, ErrInvalidPromptFile)
+	_, err = LoadPrompt(fsys, "role.yaml")
+	require.ErrorIs(t, err, ErrInvalidPromptFile)
+}
+
+func TestReloadingPrompt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	now := time.Now()
+	writeFile("prompt.yaml", `template_path: prompt.tmpl`, now)
+	writeFile("prompt.tmpl", `hello {{.name}}`, now)
+
+	p, err := NewReloadingPrompt(os.DirFS(dir), "prompt.yaml")
+	require.NoError(t, err)
+
+	value, err := p.FormatPrompt(map[string]any{"name": "foo"})
+	require.NoError(t, err)
+	require.Equal(t, "hello foo", value.String())
+
+	writeFile("prompt.tmpl", `bye {{.name}}`, now.Add(time.Second))
+
+	value, err = p.FormatPrompt(map[string]any{"name": "foo"})
+	require.NoError(t, err)
+	require.Equal(t, "bye foo", value.String())
+}
@@ -0,0 +1,225 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultMaxConcurrency = 4
+
+// DocumentCompressor filters, reorders or shortens the documents retrieved for a
+// query.
+type DocumentCompressor interface {
+	CompressDocuments(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error)
+}
+
+// DocumentCompressorPipeline is a document compressor applying document
+// compressors one after the other.
+type DocumentCompressorPipeline []DocumentCompressor
+
+var _ DocumentCompressor = DocumentCompressorPipeline{}
+
+// CompressDocuments passes the documents through the compressors in order. It
+// stops early when no documents are left.
+func (p DocumentCompressorPipeline) CompressDocuments(
+	ctx context.Context,
+	query string,
+	docs []schema.Document,
+) ([]schema.Document, error) {
+	var err error
+	for _, compressor := range p {
+		if len(docs) == 0 {
+			break
+		}
+		docs, err = compressor.CompressDocuments(ctx, query, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// ContextualCompressionRetriever is a retriever that passes the documents returned
+// by another retriever, such as a vectorstores.Retriever, through a document
+// compressor, to rerank, filter or shorten them before they are used.
+type ContextualCompressionRetriever struct {
+	CallbacksHandler callbacks.Handler
+	// Retriever is the retriever of the documents to compress.
+	Retriever schema.Retriever
+	// Compressor compresses the retrieved documents.
+	Compressor DocumentCompressor
+}
+
+var _ schema.Retriever = ContextualCompressionRetriever{}
+
+// NewContextualCompressionRetriever creates a new contextual compression retriever
+// applying the compressors in order to the documents of the retriever.
+func NewContextualCompressionRetriever(
+	retriever schema.Retriever,
+	compressors ...DocumentCompressor,
+) ContextualCompressionRetriever {
+	var compressor DocumentCompressor = DocumentCompressorPipeline(compressors)
+	if len(compressors) == 1 {
+		compressor = compressors[0]
+	}
+	return ContextualCompressionRetriever{
+		Retriever:  retriever,
+		Compressor: compressor,
+	}
+}
+
+// GetRelevantDocuments returns the compressed documents of the retriever for the
+// query.
+func (r ContextualCompressionRetriever) GetRelevantDocuments(
+	ctx context.Context,
+	query string,
+) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	docs, err := r.Retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if r.Compressor != nil && len(docs) > 0 {
+		docs, err = r.Compressor.CompressDocuments(ctx, query, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// _noOutput is the answer of the model to the extraction prompt when nothing in
+// a document is relevant.
+const _noOutput = "NO_OUTPUT"
+
+// _extractPrompt is the prompt of the extractions of LLMExtractor.
+const _extractPrompt = `Given the following question and document, extract the parts of the
+document that are relevant to answering the question, word for word. If no part of
+the document is relevant, answer ` + _noOutput + `.
+
+Question: %s
+
+Document:
+%s
+
+Relevant parts:`
+
+// LLMExtractor is a document compressor that asks a model to extract the parts of
+// every document relevant to the query. Documents without relevant parts are
+// dropped; the others keep their metadata and score.
+type LLMExtractor struct {
+	Model llms.Model
+	// CallOptions are the options of the model calls.
+	CallOptions []llms.CallOption
+	// MaxConcurrency is the maximum number of documents sent to the model at the
+	// same time.
+	MaxConcurrency int
+}
+
+var _ DocumentCompressor = LLMExtractor{}
+
+// NewLLMExtractor creates a new extractor of the relevant parts of documents.
+func NewLLMExtractor(model llms.Model, opts ...Option) LLMExtractor {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return LLMExtractor{
+		Model:          model,
+		CallOptions:    options.CallOptions,
+		MaxConcurrency: options.MaxConcurrency,
+	}
+}
+
+// CompressDocuments replaces the content of the documents by their parts relevant
+// to the query.
+func (e LLMExtractor) CompressDocuments(
+	ctx context.Context,
+	query string,
+	docs []schema.Document,
+) ([]schema.Document, error) {
+	extracted := make([]schema.Document, len(docs))
+	err := runConcurrently(ctx, len(docs), e.MaxConcurrency, func(ctx context.Context, i int) error {
+		prompt := fmt.Sprintf(_extractPrompt, query, docs[i].PageContent)
+		completion, err := llms.GenerateFromSinglePrompt(ctx, e.Model, prompt, e.CallOptions...)
+		if err != nil {
+			return err
+		}
+		extracted[i] = docs[i]
+		extracted[i].PageContent = strings.TrimSpace(completion)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := make([]schema.Document, 0, len(extracted))
+	for _, doc := range extracted {
+		if doc.PageContent == "" || strings.Contains(doc.PageContent, _noOutput) {
+			continue
+		}
+		compressed = append(compressed, doc)
+	}
+	return compressed, nil
+}
+
+// runConcurrently calls fn with the indexes 0 to n-1, at most maxConcurrency
+// calls at once, and returns the first error. The context of the calls is
+// canceled after an error.
+func runConcurrently(ctx context.Context, n, maxConcurrency int, fn func(ctx context.Context, i int) error) error {
+	if maxConcurrency <= 0 {
+		maxConcurrency = _defaultMaxConcurrency
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, maxConcurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
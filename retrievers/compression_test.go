@@ -0,0 +1,194 @@
+package retrievers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var candidates = []schema.Document{ //nolint:gochecknoglobals
+	{PageContent: "goroutines are cheap", Metadata: map[string]any{"id": 0}},
+	{PageContent: "sourdough needs a starter", Metadata: map[string]any{"id": 1}},
+	{PageContent: "channels connect goroutines", Metadata: map[string]any{"id": 2}},
+}
+
+func contents(docs []schema.Document) []string {
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	return texts
+}
+
+// scoreByDocument answers score prompts with the score of the document in them.
+func scoreByDocument(scores map[string]string) func(prompt string) string {
+	return func(prompt string) string {
+		for content, score := range scores {
+			if strings.Contains(prompt, content) {
+				return score
+			}
+		}
+		return "Score: 0"
+	}
+}
+
+func TestLLMReranker(t *testing.T) {
+	t.Parallel()
+
+	llm := &funcLLM{respond: scoreByDocument(map[string]string{
+		"goroutines are cheap":        "Score: 40",
+		"sourdough needs a starter":   "Score: 5",
+		"channels connect goroutines": "The document is relevant.\nScore: 90",
+	})}
+	reranker := retrievers.NewLLMReranker(llm, retrievers.WithScoreThreshold(0.1))
+
+	docs, err := reranker.Rerank(context.Background(), "how do goroutines communicate?", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"channels connect goroutines", "goroutines are cheap"}, contents(docs))
+	assert.InDelta(t, 0.9, docs[0].Score, 1e-6)
+	assert.Equal(t, 2, docs[0].Metadata["id"])
+	assert.Len(t, llm.prompts, 3)
+	assert.Contains(t, llm.prompts[0], "how do goroutines communicate?")
+
+	// by default all the documents are kept
+	docs, err = retrievers.NewLLMReranker(llm).Rerank(context.Background(), "goroutines", append(candidates, candidates...))
+	require.NoError(t, err)
+	assert.Len(t, docs, 2*len(candidates))
+
+	llm = &funcLLM{respond: func(string) string { return "very relevant" }}
+	_, err = retrievers.NewLLMReranker(llm).Rerank(context.Background(), "query", candidates)
+	require.ErrorIs(t, err, retrievers.ErrInvalidScore)
+}
+
+// axisEmbedder embeds texts by whether they mention goroutines and bread, and
+// texts mentioning cats opposite to everything else.
+type axisEmbedder struct{}
+
+func (axisEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = axisEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (axisEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vector := []float32{0.1, 0.1}
+	if strings.Contains(text, "cats") {
+		return []float32{-1, -1}, nil
+	}
+	if strings.Contains(text, "goroutines") {
+		vector[0] = 1
+	}
+	if strings.Contains(text, "sourdough") || strings.Contains(text, "bread") {
+		vector[1] = 1
+	}
+	return vector, nil
+}
+
+func TestEmbeddingsFilter(t *testing.T) {
+	t.Parallel()
+
+	filter := retrievers.NewEmbeddingsFilter(axisEmbedder{}, retrievers.WithScoreThreshold(0.5))
+	docs, err := filter.Rerank(context.Background(), "baking bread", candidates)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "sourdough needs a starter", docs[0].PageContent)
+	assert.InDelta(t, 1, docs[0].Score, 1e-6)
+
+	filter = retrievers.NewEmbeddingsFilter(axisEmbedder{}, retrievers.WithNumDocuments(1))
+	docs, err = filter.Rerank(context.Background(), "goroutines", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"goroutines are cheap"}, contents(docs))
+
+	// by default all the documents are kept, even the dissimilar ones
+	docs, err = retrievers.NewEmbeddingsFilter(axisEmbedder{}).Rerank(context.Background(), "cats", candidates)
+	require.NoError(t, err)
+	require.Len(t, docs, len(candidates))
+	assert.Negative(t, docs[0].Score)
+}
+
+func TestCrossEncoderReranker(t *testing.T) {
+	t.Parallel()
+
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"invalid api token"}`))
+			return
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		_, _ = w.Write([]byte(`{"id":"1","results":[
+			{"index":2,"relevance_score":0.93},
+			{"index":0,"relevance_score":0.41}
+		]}`))
+	}))
+	defer server.Close()
+
+	reranker := retrievers.NewCrossEncoderReranker(server.URL, "rerank-english-v3.0", "secret",
+		retrievers.WithNumDocuments(2), retrievers.WithHTTPClient(server.Client()))
+	docs, err := reranker.Rerank(context.Background(), "how do goroutines communicate?", candidates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"channels connect goroutines", "goroutines are cheap"}, contents(docs))
+	assert.InDelta(t, 0.93, docs[0].Score, 1e-6)
+	assert.Equal(t, map[string]any{
+		"model":     "rerank-english-v3.0",
+		"query":     "how do goroutines communicate?",
+		"documents": []any{"goroutines are cheap", "sourdough needs a starter", "channels connect goroutines"},
+		"top_n":     float64(2),
+	}, request)
+
+	reranker.APIKey = "wrong"
+	_, err = reranker.Rerank(context.Background(), "query", candidates)
+	require.ErrorIs(t, err, retrievers.ErrUnexpectedStatusCode)
+	assert.Contains(t, err.Error(), "invalid api token")
+}
+
+func TestContextualCompressionRetriever(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := &wordStore{}
+	_, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "goroutines are cheap threads"},
+		{PageContent: "goroutines talk over channels"},
+		{PageContent: "bread and goroutines"},
+	})
+	require.NoError(t, err)
+
+	scorer := &funcLLM{respond: scoreByDocument(map[string]string{
+		"goroutines are cheap threads":  "Score: 30",
+		"goroutines talk over channels": "Score: 95",
+		"bread and goroutines":          "Score: 0",
+	})}
+	extractor := &funcLLM{respond: func(prompt string) string {
+		if strings.Contains(prompt, "channels") {
+			return "talk over channels"
+		}
+		return "NO_OUTPUT"
+	}}
+
+	r := retrievers.NewContextualCompressionRetriever(
+		vectorstores.ToRetriever(store, 3),
+		retrievers.NewLLMReranker(scorer, retrievers.WithScoreThreshold(0.2)),
+		retrievers.NewLLMExtractor(extractor),
+	)
+	docs, err := r.GetRelevantDocuments(ctx, "goroutines")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "talk over channels", docs[0].PageContent)
+	assert.InDelta(t, 0.95, docs[0].Score, 1e-6)
+	assert.Len(t, scorer.prompts, 3)
+	// the document below the threshold is not sent to the extractor.
+	assert.Len(t, extractor.prompts, 2)
+}
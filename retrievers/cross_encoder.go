@@ -0,0 +1,160 @@
+package retrievers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// CohereRerankURL is the URL of the rerank endpoint of Cohere.
+	CohereRerankURL = "https://api.cohere.com/v2/rerank"
+	// JinaRerankURL is the URL of the rerank endpoint of Jina AI.
+	JinaRerankURL = "https://api.jina.ai/v1/rerank"
+)
+
+var (
+	// ErrUnexpectedStatusCode is returned when a reranking endpoint answers with a
+	// status code other than 200.
+	ErrUnexpectedStatusCode = errors.New("unexpected status code")
+	// ErrInvalidRerankResponse is returned when a reranking endpoint returns a
+	// result for a document that was not sent.
+	ErrInvalidRerankResponse = errors.New("invalid rerank response")
+)
+
+// CrossEncoderReranker is a reranker scoring documents with a cross-encoder model
+// served by an HTTP endpoint with the rerank API of Cohere, which Jina AI, Voyage
+// AI and self-hosted servers such as Text Embeddings Inference proxies also offer:
+// the query and the documents are posted as JSON, and the endpoint answers with
+// the relevance score of every document by index.
+type CrossEncoderReranker struct {
+	// URL is the URL of the rerank endpoint, such as CohereRerankURL.
+	URL string
+	// APIKey is sent as a bearer token if set.
+	APIKey string
+	// ModelName is the name of the reranking model, such as "rerank-english-v3.0".
+	ModelName string
+	// NumDocuments is the maximum number of documents returned, or all of them if
+	// not positive.
+	NumDocuments int
+	// ScoreThreshold is the relevance score below which documents are dropped, if it
+	// is not 0.
+	ScoreThreshold float32
+	// HTTPClient is the client of the requests, or http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+var _ Reranker = CrossEncoderReranker{}
+
+// NewCrossEncoderReranker creates a new reranker calling the rerank endpoint at
+// the URL with the model and API key.
+func NewCrossEncoderReranker(url, modelName, apiKey string, opts ...Option) CrossEncoderReranker {
+	options := rerankerOptions(opts)
+
+	return CrossEncoderReranker{
+		URL:            url,
+		APIKey:         apiKey,
+		ModelName:      modelName,
+		NumDocuments:   options.NumDocuments,
+		ScoreThreshold: options.ScoreThreshold,
+		HTTPClient:     options.HTTPClient,
+	}
+}
+
+type rerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+type rerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float32 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank orders the documents by the relevance scores of the endpoint.
+func (r CrossEncoderReranker) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return []schema.Document{}, nil
+	}
+
+	payload := rerankRequest{
+		Model:     r.ModelName,
+		Query:     query,
+		Documents: make([]string, len(docs)),
+		TopN:      r.NumDocuments,
+	}
+	for i, doc := range docs {
+		payload.Documents[i] = doc.PageContent
+	}
+	response, err := r.post(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	scored := make([]schema.Document, 0, len(response.Results))
+	for _, result := range response.Results {
+		if result.Index < 0 || result.Index >= len(docs) {
+			return nil, fmt.Errorf("%w: document index %d", ErrInvalidRerankResponse, result.Index)
+		}
+		doc := docs[result.Index]
+		doc.Score = result.RelevanceScore
+		scored = append(scored, doc)
+	}
+	return rankDocuments(scored, r.NumDocuments, r.ScoreThreshold), nil
+}
+
+// CompressDocuments reranks the documents.
+func (r CrossEncoderReranker) CompressDocuments(
+	ctx context.Context,
+	query string,
+	docs []schema.Document,
+) ([]schema.Document, error) {
+	return r.Rerank(ctx, query, docs)
+}
+
+func (r CrossEncoderReranker) post(ctx context.Context, payload rerankRequest) (rerankResponse, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return rerankResponse{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return rerankResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if r.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.APIKey)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return rerankResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return rerankResponse{}, fmt.Errorf("%w: %d, body: %s", ErrUnexpectedStatusCode, resp.StatusCode, string(b))
+	}
+
+	var response rerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return rerankResponse{}, err
+	}
+	return response, nil
+}
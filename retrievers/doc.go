@@ -0,0 +1,25 @@
+/*
+Package retrievers contains retrievers built on top of vector stores and language
+models that implement the schema.Retriever interface.
+
+The main components of this package are:
+
+- MultiVectorRetriever: indexes chunks, summaries or hypothetical questions of
+documents and returns the original documents from a docstore.
+- ParentDocumentRetriever: indexes small chunks of documents and returns the larger
+parent documents they were split from.
+- ContextualCompressionRetriever: passes the documents of another retriever through
+document compressors, such as rerankers and extractors.
+- LLMReranker, EmbeddingsFilter and CrossEncoderReranker: rerankers scoring
+documents with a language model, with the similarity of their embeddings, or with a
+cross-encoder served by a rerank endpoint such as the ones of Cohere or Jina AI.
+- LLMExtractor: a document compressor keeping only the parts of documents relevant
+to the query.
+- MultiQueryRetriever: retrieves the documents of paraphrases of the query written
+by a language model, in parallel, and merges them.
+- HyDERetriever: retrieves the documents of a hypothetical answer to the query
+written by a language model.
+- SelfQueryRetriever: has a language model turn the query into a search text and a
+metadata filter, which is translated to the filters of the vector store.
+*/
+package retrievers
@@ -0,0 +1,263 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/docstore"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// DefaultIDKey is the default metadata key of the id of the parent of an
+	// indexed document.
+	DefaultIDKey = "doc_id"
+
+	_defaultNumDocuments = 4
+	_defaultSearchK      = 10
+	_defaultNumQuestions = 3
+)
+
+var (
+	// ErrNoChildGenerator is returned when adding documents to a multi-vector
+	// retriever without a child generator.
+	ErrNoChildGenerator = errors.New("no child generator")
+	// ErrDuplicateID is returned when documents added to a multi-vector retriever
+	// at once have the same id.
+	ErrDuplicateID = errors.New("duplicate document id")
+)
+
+// ChildGenerator returns the documents indexed in place of a document.
+type ChildGenerator func(ctx context.Context, doc schema.Document) ([]schema.Document, error)
+
+// MultiVectorRetriever is a retriever that indexes several documents, such as
+// chunks, summaries or hypothetical questions, for every document added to it, and
+// returns the original documents. The indexed documents are searched in a vector
+// store and the original documents are read from a docstore by the id in the IDKey
+// metadata of the indexed documents.
+type MultiVectorRetriever struct {
+	CallbacksHandler callbacks.Handler
+	// Store is the vector store of the indexed documents.
+	Store vectorstores.VectorStore
+	// Docstore is the store of the original documents.
+	Docstore docstore.Docstore
+	// Generator returns the documents indexed for a document.
+	Generator ChildGenerator
+	// IDKey is the metadata key of the id of the original document.
+	IDKey string
+	// NumDocuments is the maximum number of documents returned.
+	NumDocuments int
+	// SearchK is the number of indexed documents searched.
+	SearchK int
+	// SearchOptions are the options of the vector store searches.
+	SearchOptions []vectorstores.Option
+}
+
+var _ schema.Retriever = MultiVectorRetriever{}
+
+// NewMultiVectorRetriever creates a new multi-vector retriever indexing the documents
+// returned by the generator, such as ChildrenFromSummary or ChildrenFromQuestions.
+func NewMultiVectorRetriever(
+	store vectorstores.VectorStore,
+	docs docstore.Docstore,
+	generator ChildGenerator,
+	opts ...Option,
+) MultiVectorRetriever {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return MultiVectorRetriever{
+		Store:         store,
+		Docstore:      docs,
+		Generator:     generator,
+		IDKey:         options.IDKey,
+		NumDocuments:  options.NumDocuments,
+		SearchK:       options.SearchK,
+		SearchOptions: options.SearchOptions,
+	}
+}
+
+// AddDocuments indexes the documents generated for the documents and stores the
+// documents in the docstore. Documents are stored with the id in their IDKey
+// metadata if it is a string, or a new id otherwise. Documents with the same id
+// are rejected, as only one of them would be stored. It returns the ids of the
+// documents.
+func (r MultiVectorRetriever) AddDocuments(ctx context.Context, docs []schema.Document) ([]string, error) {
+	if r.Generator == nil {
+		return nil, ErrNoChildGenerator
+	}
+
+	ids := make([]string, len(docs))
+	seen := make(map[string]bool, len(docs))
+	for i, doc := range docs {
+		id, ok := doc.Metadata[r.IDKey].(string)
+		if !ok || id == "" {
+			id = uuid.New().String()
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateID, id)
+		}
+		seen[id] = true
+		ids[i] = id
+	}
+
+	children := make([]schema.Document, 0, len(docs))
+	for i, doc := range docs {
+		id := ids[i]
+
+		generated, err := r.Generator(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range generated {
+			metadata := make(map[string]any, len(child.Metadata)+1)
+			for key, value := range child.Metadata {
+				metadata[key] = value
+			}
+			metadata[r.IDKey] = id
+			child.Metadata = metadata
+			children = append(children, child)
+		}
+	}
+
+	if len(children) > 0 {
+		if _, err := r.Store.AddDocuments(ctx, children); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.Docstore.Set(ctx, ids, docs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetRelevantDocuments returns the original documents of the indexed documents most
+// similar to the query, in the order of their best match.
+func (r MultiVectorRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	searchK := r.SearchK
+	if searchK <= 0 {
+		searchK = _defaultSearchK
+	}
+	children, err := r.Store.SimilaritySearch(ctx, query, searchK, r.SearchOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	numDocuments := r.NumDocuments
+	if numDocuments <= 0 {
+		numDocuments = _defaultNumDocuments
+	}
+	ids := make([]string, 0, numDocuments)
+	seen := make(map[string]bool)
+	for _, child := range children {
+		id, ok := child.Metadata[r.IDKey].(string)
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		if len(ids) == numDocuments {
+			break
+		}
+	}
+
+	docs, err := r.Docstore.Get(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// ChildrenFromSplitter returns a child generator splitting documents into chunks.
+func ChildrenFromSplitter(splitter textsplitter.TextSplitter) ChildGenerator {
+	return func(_ context.Context, doc schema.Document) ([]schema.Document, error) {
+		return textsplitter.SplitDocuments(splitter, []schema.Document{doc})
+	}
+}
+
+// _summaryPrompt is the prompt of the summaries of ChildrenFromSummary.
+const _summaryPrompt = `Summarize the following document in a few sentences, keeping the facts
+someone might search it for.
+
+Document:
+%s
+
+Summary:`
+
+// ChildrenFromSummary returns a child generator indexing a summary of documents
+// written by the model.
+func ChildrenFromSummary(model llms.Model, options ...llms.CallOption) ChildGenerator {
+	return func(ctx context.Context, doc schema.Document) ([]schema.Document, error) {
+		summary, err := llms.GenerateFromSinglePrompt(ctx, model, fmt.Sprintf(_summaryPrompt, doc.PageContent), options...)
+		if err != nil {
+			return nil, err
+		}
+		return []schema.Document{{PageContent: strings.TrimSpace(summary)}}, nil
+	}
+}
+
+// _questionsPrompt is the prompt of the questions of ChildrenFromQuestions.
+const _questionsPrompt = `Write %d questions the following document answers, one per line, without
+numbering them.
+
+Document:
+%s
+
+Questions:`
+
+// ChildrenFromQuestions returns a child generator indexing hypothetical questions
+// the model writes that documents answer. A non-positive n asks for 3 questions.
+func ChildrenFromQuestions(model llms.Model, n int, options ...llms.CallOption) ChildGenerator {
+	if n <= 0 {
+		n = _defaultNumQuestions
+	}
+	return func(ctx context.Context, doc schema.Document) ([]schema.Document, error) {
+		prompt := fmt.Sprintf(_questionsPrompt, n, doc.PageContent)
+		completion, err := llms.GenerateFromSinglePrompt(ctx, model, prompt, options...)
+		if err != nil {
+			return nil, err
+		}
+
+		questions := make([]schema.Document, 0, n)
+		for _, line := range parseLines(completion) {
+			questions = append(questions, schema.Document{PageContent: line})
+			if len(questions) == n {
+				break
+			}
+		}
+		return questions, nil
+	}
+}
+
+var _listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s*`) //nolint:gochecknoglobals
+
+// parseLines returns the non-empty lines of a completion, without list markers.
+func parseLines(completion string) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(completion, "\n") {
+		line = _listMarker.ReplaceAllString(strings.TrimSpace(line), "")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
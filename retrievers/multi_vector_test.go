@@ -0,0 +1,168 @@
+package retrievers_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/docstore"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+var parents = []schema.Document{ //nolint:gochecknoglobals
+	{
+		PageContent: "Go is a programming language. Its goroutines make concurrency cheap.",
+		Metadata:    map[string]any{"source": "go.txt"},
+	},
+	{
+		PageContent: "Bread is baked from flour. Sourdough uses a wild yeast starter.",
+		Metadata:    map[string]any{"source": "bread.txt"},
+	},
+}
+
+func TestParentDocumentRetriever(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := &wordStore{}
+	r := retrievers.NewParentDocumentRetriever(store, docstore.NewInMemory(),
+		textsplitter.NewRecursiveCharacter(
+			textsplitter.WithChunkSize(40),
+			textsplitter.WithChunkOverlap(0),
+			textsplitter.WithSeparators([]string{". "}),
+		),
+		retrievers.WithNumDocuments(1),
+	)
+
+	ids, err := r.AddDocuments(ctx, parents)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Greater(t, len(store.docs), 2)
+	for _, child := range store.docs {
+		assert.Contains(t, ids, child.Metadata[retrievers.DefaultIDKey])
+	}
+
+	docs, err := r.GetRelevantDocuments(ctx, "wild yeast")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, parents[1], docs[0])
+}
+
+func TestParentDocumentRetrieverParentSplitter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	r := retrievers.NewParentDocumentRetriever(&wordStore{}, docstore.NewInMemory(),
+		textsplitter.NewRecursiveCharacter(textsplitter.WithChunkSize(10), textsplitter.WithChunkOverlap(0)),
+		retrievers.WithParentSplitter(textsplitter.NewRecursiveCharacter(
+			textsplitter.WithChunkSize(40),
+			textsplitter.WithChunkOverlap(0),
+			textsplitter.WithSeparators([]string{". "}),
+		)),
+	)
+
+	ids, err := r.AddDocuments(ctx, parents[:1])
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	docs, err := r.GetRelevantDocuments(ctx, "goroutines")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Contains(t, docs[0].PageContent, "goroutines")
+	assert.NotContains(t, docs[0].PageContent, "programming")
+	assert.Equal(t, "go.txt", docs[0].Metadata["source"])
+
+	// the parents of a document with an id get their own ids
+	withID := schema.Document{
+		PageContent: parents[1].PageContent,
+		Metadata:    map[string]any{retrievers.DefaultIDKey: "bread"},
+	}
+	ids, err = r.AddDocuments(ctx, []schema.Document{withID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bread/0", "bread/1"}, ids)
+
+	docs, err = r.Docstore.Get(ctx, ids)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Contains(t, docs[0].PageContent, "flour")
+	assert.Contains(t, docs[1].PageContent, "wild yeast")
+}
+
+func TestMultiVectorRetrieverDuplicateID(t *testing.T) {
+	t.Parallel()
+
+	store := &wordStore{}
+	r := retrievers.NewMultiVectorRetriever(store, docstore.NewInMemory(),
+		retrievers.ChildrenFromSplitter(textsplitter.NewRecursiveCharacter()))
+
+	docs := []schema.Document{
+		{PageContent: "a", Metadata: map[string]any{retrievers.DefaultIDKey: "same"}},
+		{PageContent: "b", Metadata: map[string]any{retrievers.DefaultIDKey: "same"}},
+	}
+	_, err := r.AddDocuments(context.Background(), docs)
+	require.ErrorIs(t, err, retrievers.ErrDuplicateID)
+	assert.Empty(t, store.docs)
+}
+
+func TestMultiVectorRetriever(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &funcLLM{respond: func(prompt string) string {
+		switch {
+		case strings.Contains(prompt, "Summarize") && strings.Contains(prompt, "Bread"):
+			return "How to bake bread."
+		case strings.Contains(prompt, "Summarize"):
+			return "About the Go language."
+		case strings.Contains(prompt, "Bread"):
+			return "1. What is sourdough?\n2. What is bread made of?\n3. Extra question?\n4. One too many?"
+		default:
+			return "- What are goroutines?\n- Who made Go?"
+		}
+	}}
+
+	t.Run("summaries", func(t *testing.T) {
+		t.Parallel()
+
+		store := &wordStore{}
+		r := retrievers.NewMultiVectorRetriever(store, docstore.NewInMemory(), retrievers.ChildrenFromSummary(llm))
+		_, err := r.AddDocuments(ctx, parents)
+		require.NoError(t, err)
+		require.Len(t, store.docs, 2)
+		assert.Equal(t, "How to bake bread.", store.docs[1].PageContent)
+
+		docs, err := r.GetRelevantDocuments(ctx, "bake")
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, parents[1], docs[0])
+	})
+
+	t.Run("questions", func(t *testing.T) {
+		t.Parallel()
+
+		store := &wordStore{}
+		r := retrievers.NewMultiVectorRetriever(store, docstore.NewInMemory(), retrievers.ChildrenFromQuestions(llm, 3))
+		_, err := r.AddDocuments(ctx, parents)
+		require.NoError(t, err)
+		contents := make([]string, 0, len(store.docs))
+		for _, doc := range store.docs {
+			contents = append(contents, doc.PageContent)
+		}
+		assert.Equal(t, []string{
+			"What are goroutines?", "Who made Go?",
+			"What is sourdough?", "What is bread made of?", "Extra question?",
+		}, contents)
+
+		docs, err := r.GetRelevantDocuments(ctx, "goroutines")
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, parents[0], docs[0])
+	})
+
+	_, err := retrievers.MultiVectorRetriever{}.AddDocuments(ctx, parents)
+	require.ErrorIs(t, err, retrievers.ErrNoChildGenerator)
+}
@@ -0,0 +1,126 @@
+package retrievers
+
+import (
+	"net/http"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// Options are the options of the retrievers.
+type Options struct {
+	IDKey          string
+	NumDocuments   int
+	SearchK        int
+	SearchOptions  []vectorstores.Option
+	ParentSplitter textsplitter.TextSplitter
+	ScoreThreshold float32
+	MaxConcurrency int
+	CallOptions    []llms.CallOption
+	HTTPClient     *http.Client
+
+	NumQueries           int
+	IncludeOriginalQuery bool
+}
+
+// Option is a function that can be used to set options of the retrievers.
+type Option func(*Options)
+
+func defaultOptions() Options {
+	return Options{
+		IDKey:          DefaultIDKey,
+		NumDocuments:   _defaultNumDocuments,
+		SearchK:        _defaultSearchK,
+		MaxConcurrency: _defaultMaxConcurrency,
+		NumQueries:     _defaultNumQuestions,
+	}
+}
+
+// WithIDKey sets the metadata key of the id of the parent of an indexed document.
+// The default is "doc_id".
+func WithIDKey(key string) Option {
+	return func(o *Options) {
+		o.IDKey = key
+	}
+}
+
+// WithNumDocuments sets the maximum number of documents returned. The default is 4,
+// except for rerankers, which return all the documents by default.
+func WithNumDocuments(n int) Option {
+	return func(o *Options) {
+		o.NumDocuments = n
+	}
+}
+
+// WithSearchK sets the number of indexed documents searched in the vector store.
+// Several indexed documents can have the same parent, so it should be larger than the
+// number of documents returned. The default is 10.
+func WithSearchK(k int) Option {
+	return func(o *Options) {
+		o.SearchK = k
+	}
+}
+
+// WithSearchOptions sets the options of the vector store searches.
+func WithSearchOptions(options ...vectorstores.Option) Option {
+	return func(o *Options) {
+		o.SearchOptions = options
+	}
+}
+
+// WithParentSplitter sets the splitter the documents added to a parent document
+// retriever are split into parent documents with. By default whole documents are
+// the parents.
+func WithParentSplitter(splitter textsplitter.TextSplitter) Option {
+	return func(o *Options) {
+		o.ParentSplitter = splitter
+	}
+}
+
+// WithScoreThreshold sets the relevance score below which rerankers drop
+// documents. The default is 0, which drops no documents.
+func WithScoreThreshold(threshold float32) Option {
+	return func(o *Options) {
+		o.ScoreThreshold = threshold
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of documents a reranker or extractor
+// sends to a model at the same time. The default is 4.
+func WithMaxConcurrency(n int) Option {
+	return func(o *Options) {
+		o.MaxConcurrency = n
+	}
+}
+
+// WithCallOptions sets the options of the model calls.
+func WithCallOptions(options ...llms.CallOption) Option {
+	return func(o *Options) {
+		o.CallOptions = options
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the requests to a reranking endpoint. The
+// default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *Options) {
+		o.HTTPClient = client
+	}
+}
+
+// WithNumQueries sets the number of paraphrases of the query a multi-query
+// retriever asks for. The default is 3.
+func WithNumQueries(n int) Option {
+	return func(o *Options) {
+		o.NumQueries = n
+	}
+}
+
+// WithIncludeOriginalQuery makes a multi-query retriever retrieve the documents of
+// the query itself too, before the ones of its paraphrases.
+func WithIncludeOriginalQuery(include bool) Option {
+	return func(o *Options) {
+		o.IncludeOriginalQuery = include
+	}
+}
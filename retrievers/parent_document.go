@@ -0,0 +1,67 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/docstore"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ParentDocumentRetriever is a retriever that indexes small chunks of documents,
+// which embed well, and returns the larger parent documents they were split from,
+// which give the model more context. The parents are whole documents, or chunks of
+// them split with ParentSplitter.
+type ParentDocumentRetriever struct {
+	MultiVectorRetriever
+	// ParentSplitter splits documents into parents, if it is not nil.
+	ParentSplitter textsplitter.TextSplitter
+}
+
+var _ schema.Retriever = ParentDocumentRetriever{}
+
+// NewParentDocumentRetriever creates a new parent document retriever indexing the
+// chunks of the child splitter.
+func NewParentDocumentRetriever(
+	store vectorstores.VectorStore,
+	docs docstore.Docstore,
+	childSplitter textsplitter.TextSplitter,
+	opts ...Option,
+) ParentDocumentRetriever {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return ParentDocumentRetriever{
+		MultiVectorRetriever: NewMultiVectorRetriever(store, docs, ChildrenFromSplitter(childSplitter), opts...),
+		ParentSplitter:       options.ParentSplitter,
+	}
+}
+
+// AddDocuments splits the documents into parents, indexes the chunks of the parents
+// and stores the parents in the docstore. It returns the ids of the parents. The
+// parents split from a document with an id in its IDKey metadata get the ids
+// "<id>/0", "<id>/1" and so on.
+func (r ParentDocumentRetriever) AddDocuments(ctx context.Context, docs []schema.Document) ([]string, error) {
+	if r.ParentSplitter == nil {
+		return r.MultiVectorRetriever.AddDocuments(ctx, docs)
+	}
+
+	parents := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		split, err := textsplitter.SplitDocuments(r.ParentSplitter, []schema.Document{doc})
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := doc.Metadata[r.IDKey].(string); ok && id != "" {
+			for n := range split {
+				split[n].Metadata[r.IDKey] = fmt.Sprintf("%s/%d", id, n)
+			}
+		}
+		parents = append(parents, split...)
+	}
+	return r.MultiVectorRetriever.AddDocuments(ctx, parents)
+}
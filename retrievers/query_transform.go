@@ -0,0 +1,237 @@
+package retrievers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _multiQueryPrompt is the prompt of the paraphrases of MultiQueryRetriever.
+const _multiQueryPrompt = `Write %d different versions of the following question, to retrieve
+relevant documents from a vector store with each of them. Use different words and
+perspectives. Write one question per line, without numbering them.
+
+Question: %s
+
+Questions:`
+
+// MultiQueryRetriever is a retriever that asks a model for paraphrases of the
+// query, retrieves documents for every paraphrase in parallel and merges them.
+// Short or ambiguous queries find more relevant documents this way.
+//
+// The paraphrases are reported to the CallbacksHandler as retriever events of
+// their own, nested in the events of the query.
+type MultiQueryRetriever struct {
+	CallbacksHandler callbacks.Handler
+	// Retriever is the retriever of the documents of the queries.
+	Retriever schema.Retriever
+	// Model writes the paraphrases.
+	Model llms.Model
+	// CallOptions are the options of the model calls.
+	CallOptions []llms.CallOption
+	// NumQueries is the number of paraphrases asked for.
+	NumQueries int
+	// IncludeOriginalQuery makes the query itself be retrieved for too.
+	IncludeOriginalQuery bool
+	// MaxConcurrency is the maximum number of queries retrieved at the same time.
+	MaxConcurrency int
+}
+
+var _ schema.Retriever = MultiQueryRetriever{}
+
+// NewMultiQueryRetriever creates a new multi-query retriever retrieving the
+// documents of the paraphrases the model writes with the retriever.
+func NewMultiQueryRetriever(retriever schema.Retriever, model llms.Model, opts ...Option) MultiQueryRetriever {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return MultiQueryRetriever{
+		Retriever:            retriever,
+		Model:                model,
+		CallOptions:          options.CallOptions,
+		NumQueries:           options.NumQueries,
+		IncludeOriginalQuery: options.IncludeOriginalQuery,
+		MaxConcurrency:       options.MaxConcurrency,
+	}
+}
+
+// GetRelevantDocuments returns the documents of the queries, in the order of the
+// queries and of their results. Documents with the same content are returned once.
+func (r MultiQueryRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	queries, err := r.queries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]schema.Document, len(queries))
+	err = runConcurrently(ctx, len(queries), r.MaxConcurrency, func(ctx context.Context, i int) error {
+		docs, err := retrieveSubQuery(ctx, r.CallbacksHandler, r.Retriever, queries[i])
+		results[i] = docs
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	docs := uniqueDocuments(results)
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// queries returns the queries documents are retrieved for: the query if it is
+// included, followed by the distinct paraphrases of the model.
+func (r MultiQueryRetriever) queries(ctx context.Context, query string) ([]string, error) {
+	n := r.NumQueries
+	if n <= 0 {
+		n = _defaultNumQuestions
+	}
+	prompt := fmt.Sprintf(_multiQueryPrompt, n, query)
+	completion, err := llms.GenerateFromSinglePrompt(ctx, r.Model, prompt, r.CallOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]string, 0, n+1)
+	seen := make(map[string]bool)
+	if r.IncludeOriginalQuery {
+		queries = append(queries, query)
+		seen[query] = true
+	}
+	paraphrases := 0
+	for _, line := range parseLines(completion) {
+		if paraphrases == n {
+			break
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		queries = append(queries, line)
+		paraphrases++
+	}
+	if len(queries) == 0 {
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// _hydePrompt is the prompt of the hypothetical answers of HyDERetriever.
+const _hydePrompt = `Write a short passage that answers the following question, in the style of
+a document that could contain the answer.
+
+Question: %s
+
+Passage:`
+
+// HyDERetriever is a retriever implementing Hypothetical Document Embeddings: it
+// asks a model for a hypothetical answer to the query and retrieves the documents
+// of the answer instead of the query. A vector store retriever embeds the answer,
+// which is usually closer to the relevant documents than a short question.
+//
+// The hypothetical answer is reported to the CallbacksHandler as a retriever event
+// of its own, nested in the events of the query.
+type HyDERetriever struct {
+	CallbacksHandler callbacks.Handler
+	// Retriever is the retriever of the documents of the hypothetical answers.
+	Retriever schema.Retriever
+	// Model writes the hypothetical answers.
+	Model llms.Model
+	// CallOptions are the options of the model calls.
+	CallOptions []llms.CallOption
+}
+
+var _ schema.Retriever = HyDERetriever{}
+
+// NewHyDERetriever creates a new HyDE retriever retrieving the documents of the
+// hypothetical answers the model writes with the retriever.
+func NewHyDERetriever(retriever schema.Retriever, model llms.Model, opts ...Option) HyDERetriever {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return HyDERetriever{
+		Retriever:   retriever,
+		Model:       model,
+		CallOptions: options.CallOptions,
+	}
+}
+
+// GetRelevantDocuments returns the documents of a hypothetical answer to the query.
+func (r HyDERetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	prompt := fmt.Sprintf(_hydePrompt, query)
+	answer, err := llms.GenerateFromSinglePrompt(ctx, r.Model, prompt, r.CallOptions...)
+	if err != nil {
+		return nil, err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		answer = query
+	}
+
+	docs, err := retrieveSubQuery(ctx, r.CallbacksHandler, r.Retriever, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// retrieveSubQuery returns the documents of a query derived from the query of a
+// retriever, reporting it to the handler.
+func retrieveSubQuery(
+	ctx context.Context,
+	handler callbacks.Handler,
+	retriever schema.Retriever,
+	query string,
+) ([]schema.Document, error) {
+	if handler != nil {
+		handler.HandleRetrieverStart(ctx, query)
+	}
+	docs, err := retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if handler != nil {
+		handler.HandleRetrieverEnd(ctx, query, docs)
+	}
+	return docs, nil
+}
+
+// uniqueDocuments concatenates the results, keeping the first of the documents
+// with the same content.
+func uniqueDocuments(results [][]schema.Document) []schema.Document {
+	docs := make([]schema.Document, 0)
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, doc := range result {
+			if seen[doc.PageContent] {
+				continue
+			}
+			seen[doc.PageContent] = true
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
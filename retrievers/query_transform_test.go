@@ -0,0 +1,103 @@
+package retrievers_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// retrieverEvents records the queries of the retriever events.
+type retrieverEvents struct {
+	callbacks.SimpleHandler
+	mu     sync.Mutex
+	starts []string
+	ends   map[string]int
+}
+
+func (h *retrieverEvents) HandleRetrieverStart(_ context.Context, query string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.starts = append(h.starts, query)
+}
+
+func (h *retrieverEvents) HandleRetrieverEnd(_ context.Context, query string, docs []schema.Document) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.ends == nil {
+		h.ends = make(map[string]int)
+	}
+	h.ends[query] = len(docs)
+}
+
+func newLanguageStore(t *testing.T) *wordStore {
+	t.Helper()
+
+	store := &wordStore{}
+	_, err := store.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "Go has goroutines"},
+		{PageContent: "Rust has ownership"},
+		{PageContent: "Go and Rust compile to machine code"},
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestMultiQueryRetriever(t *testing.T) {
+	t.Parallel()
+
+	store := newLanguageStore(t)
+	llm := &funcLLM{respond: func(string) string {
+		return "1. goroutines\n2. ownership\n\n3. goroutines\n4. lifetimes"
+	}}
+	handler := &retrieverEvents{}
+	r := retrievers.NewMultiQueryRetriever(vectorstores.ToRetriever(store, 2), llm,
+		retrievers.WithIncludeOriginalQuery(true))
+	r.CallbacksHandler = handler
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "compile")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Go and Rust compile to machine code",
+		"Go has goroutines",
+		"Rust has ownership",
+	}, contents(docs))
+	assert.Contains(t, llm.prompts[0], "Write 3 different versions")
+
+	// the duplicated paraphrase is retrieved once, and the fourth is not asked for.
+	assert.ElementsMatch(t, []string{"compile", "goroutines", "ownership", "lifetimes"}, store.searches)
+	assert.ElementsMatch(t, []string{"compile", "compile", "goroutines", "ownership", "lifetimes"}, handler.starts)
+	assert.Equal(t, map[string]int{"compile": 3, "goroutines": 1, "ownership": 1, "lifetimes": 0}, handler.ends)
+	assert.Equal(t, "compile", handler.starts[0])
+}
+
+func TestHyDERetriever(t *testing.T) {
+	t.Parallel()
+
+	store := newLanguageStore(t)
+	llm := &funcLLM{respond: func(prompt string) string {
+		if strings.Contains(prompt, "memory safety") {
+			return " Rust guarantees memory safety through ownership. "
+		}
+		return ""
+	}}
+	handler := &retrieverEvents{}
+	r := retrievers.NewHyDERetriever(vectorstores.ToRetriever(store, 1), llm)
+	r.CallbacksHandler = handler
+
+	docs, err := r.GetRelevantDocuments(context.Background(), "how does Rust get memory safety?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Rust has ownership"}, contents(docs))
+
+	answer := "Rust guarantees memory safety through ownership."
+	assert.Equal(t, []string{answer}, store.searches)
+	assert.Equal(t, []string{"how does Rust get memory safety?", answer}, handler.starts)
+	assert.Equal(t, map[string]int{"how does Rust get memory safety?": 1, answer: 1}, handler.ends)
+}
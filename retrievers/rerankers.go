@@ -0,0 +1,243 @@
+package retrievers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrInvalidScore is returned when the relevance score of a document cannot be
+// read from the answer of a model.
+var ErrInvalidScore = errors.New("invalid relevance score")
+
+// ErrMismatchedEmbeddings is returned when an embedder does not return one
+// embedding per document.
+var ErrMismatchedEmbeddings = errors.New("number of embeddings does not match number of documents")
+
+// Reranker orders documents by their relevance to a query, most relevant first,
+// sets their Score to the relevance, and may drop the less relevant documents.
+// Rerankers are also document compressors, so they can be used in a
+// ContextualCompressionRetriever.
+type Reranker interface {
+	DocumentCompressor
+	Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error)
+}
+
+// _scorePrompt is the prompt of the scores of LLMReranker. Like the map rerank
+// prompt of the question answering chains, it asks for a score between 0 and 100.
+const _scorePrompt = `How relevant is the following document to answering the question? Answer
+only with "Score: " followed by a score between 0 and 100, where 0 is irrelevant
+and 100 means it fully answers the question.
+
+Question: %s
+
+Document:
+%s
+
+Score:`
+
+var (
+	_scoreLine = regexp.MustCompile(`(?i)score:\s*(\d+(?:\.\d+)?)`) //nolint:gochecknoglobals
+	_number    = regexp.MustCompile(`\d+(?:\.\d+)?`)                //nolint:gochecknoglobals
+)
+
+const _maxLLMScore = 100
+
+// LLMReranker is a reranker asking a model to score the relevance of every
+// document between 0 and 100. The scores are divided by 100, so the Score of the
+// documents is between 0 and 1.
+type LLMReranker struct {
+	Model llms.Model
+	// CallOptions are the options of the model calls.
+	CallOptions []llms.CallOption
+	// NumDocuments is the maximum number of documents returned, or all of them if
+	// not positive.
+	NumDocuments int
+	// ScoreThreshold is the score below which documents are dropped, if it is not 0.
+	ScoreThreshold float32
+	// MaxConcurrency is the maximum number of documents sent to the model at the
+	// same time.
+	MaxConcurrency int
+}
+
+var _ Reranker = LLMReranker{}
+
+// NewLLMReranker creates a new reranker scoring documents with the model.
+func NewLLMReranker(model llms.Model, opts ...Option) LLMReranker {
+	options := rerankerOptions(opts)
+
+	return LLMReranker{
+		Model:          model,
+		CallOptions:    options.CallOptions,
+		NumDocuments:   options.NumDocuments,
+		ScoreThreshold: options.ScoreThreshold,
+		MaxConcurrency: options.MaxConcurrency,
+	}
+}
+
+// Rerank orders the documents by the scores of the model.
+func (r LLMReranker) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	scored := make([]schema.Document, len(docs))
+	err := runConcurrently(ctx, len(docs), r.MaxConcurrency, func(ctx context.Context, i int) error {
+		prompt := fmt.Sprintf(_scorePrompt, query, docs[i].PageContent)
+		completion, err := llms.GenerateFromSinglePrompt(ctx, r.Model, prompt, r.CallOptions...)
+		if err != nil {
+			return err
+		}
+		score, err := parseScore(completion)
+		if err != nil {
+			return err
+		}
+		scored[i] = docs[i]
+		scored[i].Score = score / _maxLLMScore
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rankDocuments(scored, r.NumDocuments, r.ScoreThreshold), nil
+}
+
+// CompressDocuments reranks the documents.
+func (r LLMReranker) CompressDocuments(
+	ctx context.Context,
+	query string,
+	docs []schema.Document,
+) ([]schema.Document, error) {
+	return r.Rerank(ctx, query, docs)
+}
+
+// parseScore reads the score of a "Score: " line of a completion, or else the
+// first number in it, and clamps it between 0 and 100.
+func parseScore(completion string) (float32, error) {
+	text := _number.FindString(completion)
+	if match := _scoreLine.FindStringSubmatch(completion); match != nil {
+		text = match[1]
+	}
+	if text == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidScore, completion)
+	}
+	score, err := strconv.ParseFloat(text, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidScore, err)
+	}
+	return float32(math.Min(score, _maxLLMScore)), nil
+}
+
+// EmbeddingsFilter is a reranker ordering documents by the cosine similarity of
+// their embeddings to the embedding of the query. With a ScoreThreshold it filters
+// out the documents that are not similar enough.
+type EmbeddingsFilter struct {
+	Embedder embeddings.Embedder
+	// NumDocuments is the maximum number of documents returned, or all of them if
+	// not positive.
+	NumDocuments int
+	// ScoreThreshold is the similarity below which documents are dropped, if it is
+	// not 0.
+	ScoreThreshold float32
+}
+
+var _ Reranker = EmbeddingsFilter{}
+
+// NewEmbeddingsFilter creates a new reranker comparing the embeddings of the
+// embedder.
+func NewEmbeddingsFilter(embedder embeddings.Embedder, opts ...Option) EmbeddingsFilter {
+	options := rerankerOptions(opts)
+
+	return EmbeddingsFilter{
+		Embedder:       embedder,
+		NumDocuments:   options.NumDocuments,
+		ScoreThreshold: options.ScoreThreshold,
+	}
+}
+
+// Rerank orders the documents by the similarity of their embeddings to the
+// embedding of the query.
+func (f EmbeddingsFilter) Rerank(ctx context.Context, query string, docs []schema.Document) ([]schema.Document, error) {
+	if len(docs) == 0 {
+		return []schema.Document{}, nil
+	}
+	queryVector, err := f.Embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.PageContent
+	}
+	vectors, err := f.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("%w: %d embeddings for %d documents", ErrMismatchedEmbeddings, len(vectors), len(docs))
+	}
+
+	scored := make([]schema.Document, len(docs))
+	for i, doc := range docs {
+		doc.Score = float32(cosineSimilarity(queryVector, vectors[i]))
+		scored[i] = doc
+	}
+	return rankDocuments(scored, f.NumDocuments, f.ScoreThreshold), nil
+}
+
+// CompressDocuments reranks the documents.
+func (f EmbeddingsFilter) CompressDocuments(
+	ctx context.Context,
+	query string,
+	docs []schema.Document,
+) ([]schema.Document, error) {
+	return f.Rerank(ctx, query, docs)
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// rerankerOptions applies the options of a reranker. Unlike retrievers, rerankers
+// return all the documents by default.
+func rerankerOptions(opts []Option) Options {
+	options := defaultOptions()
+	options.NumDocuments = 0
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// rankDocuments drops the documents scoring below the threshold, if it is not 0,
+// and returns at most numDocuments of the others, highest score first. Documents
+// with the same score keep their order.
+func rankDocuments(docs []schema.Document, numDocuments int, threshold float32) []schema.Document {
+	ranked := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if threshold == 0 || doc.Score >= threshold {
+			ranked = append(ranked, doc)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	if numDocuments > 0 && len(ranked) > numDocuments {
+		ranked = ranked[:numDocuments]
+	}
+	return ranked
+}
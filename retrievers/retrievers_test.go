@@ -0,0 +1,101 @@
+package retrievers_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// wordStore is a vector store scoring documents by the share of the words of the
+// query they contain. It records the queries and filters of the searches.
+type wordStore struct {
+	mu       sync.Mutex
+	docs     []schema.Document
+	searches []string
+	filters  []any
+}
+
+func (s *wordStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, len(docs))
+	s.docs = append(s.docs, docs...)
+	return ids, nil
+}
+
+func (s *wordStore) SimilaritySearch(
+	_ context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searches = append(s.searches, query)
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	s.filters = append(s.filters, opts.Filters)
+
+	words := strings.Fields(strings.ToLower(query))
+	scored := make([]schema.Document, 0)
+	for _, doc := range s.docs {
+		content := strings.ToLower(doc.PageContent)
+		matches := 0
+		for _, word := range words {
+			if strings.Contains(content, word) {
+				matches++
+			}
+		}
+		if matches > 0 {
+			doc.Score = float32(matches) / float32(len(words))
+			scored = append(scored, doc)
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	if len(scored) > numDocuments {
+		scored = scored[:numDocuments]
+	}
+	return scored, nil
+}
+
+// funcLLM is a model answering prompts with a function.
+type funcLLM struct {
+	mu      sync.Mutex
+	respond func(prompt string) string
+	prompts []string
+}
+
+func (l *funcLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, l, prompt, options...)
+}
+
+func (l *funcLLM) GenerateContent(
+	_ context.Context,
+	messages []llms.MessageContent,
+	_ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	var prompt strings.Builder
+	for _, message := range messages {
+		for _, part := range message.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				prompt.WriteString(text.Text)
+			}
+		}
+	}
+
+	l.mu.Lock()
+	l.prompts = append(l.prompts, prompt.String())
+	l.mu.Unlock()
+
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content: l.respond(prompt.String()),
+	}}}, nil
+}
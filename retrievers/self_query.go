@@ -0,0 +1,307 @@
+package retrievers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrInvalidStructuredQuery is returned when the structured query written by a
+// model cannot be parsed or filters on attributes that are not described.
+var ErrInvalidStructuredQuery = errors.New("invalid structured query")
+
+// AttributeInfo describes a metadata attribute of the documents that queries can
+// filter on.
+type AttributeInfo struct {
+	Name string
+	// Type is the type of the values, such as "string", "integer", "float",
+	// "boolean" or "date (RFC 3339 string)".
+	Type        string
+	Description string
+}
+
+// StructuredQuery is a query split into the text compared to the contents of the
+// documents and a filter of their metadata.
+type StructuredQuery struct {
+	Query string
+	// Filter is nil if the query does not restrict the metadata.
+	Filter vectorstores.Filter
+}
+
+// _selfQueryPrompt is the prompt of the structured queries of SelfQueryRetriever.
+const _selfQueryPrompt = `Turn the user's question into a structured query of a store of documents.
+
+The documents contain: %s
+
+Their metadata has the following attributes:
+%s
+
+Answer with a JSON object and nothing else, with the keys:
+- "query": the text to compare to the contents of the documents, without the
+conditions on the metadata. Use "" if there is nothing left to compare.
+- "filter": the conditions on the metadata, or null if there are none.
+
+A condition is either a comparison:
+{"comparator": "<comparator>", "attribute": "<attribute>", "value": <value>}
+where the comparator is one of eq, ne, gt, gte, lt, lte, in, nin, and the value of
+in and nin is a list, or an operation:
+{"operator": "<operator>", "arguments": [<condition>, ...]}
+where the operator is one of and, or, not, and not has a single argument.
+
+Only use the attributes listed above, and values of their type. Write dates as
+RFC 3339 strings, such as "2023-01-01T00:00:00Z".
+
+Example: for the question "reports from 2023 about pricing" of documents with a
+"date" attribute, answer
+{"query": "pricing", "filter": {"operator": "and", "arguments": [
+{"comparator": "gte", "attribute": "date", "value": "2023-01-01T00:00:00Z"},
+{"comparator": "lt", "attribute": "date", "value": "2024-01-01T00:00:00Z"}]}}
+
+Question: %s
+
+Structured query:`
+
+// SelfQueryRetriever is a retriever that asks a model to turn the query into a
+// StructuredQuery, given a description of the metadata of the documents, and
+// searches the vector store for the text of the structured query with its
+// filter. Conditions such as date ranges are thereby applied as metadata filters
+// instead of being left to the similarity of embeddings.
+//
+// The filter is translated to the filters of the store by the Translator, such as
+// pgvector.TranslateFilter, qdrant.TranslateFilter, chroma.TranslateFilter or
+// weaviate.TranslateFilter. The text of the structured query is reported to the
+// CallbacksHandler as a retriever event of its own, nested in the events of the
+// query.
+type SelfQueryRetriever struct {
+	CallbacksHandler callbacks.Handler
+	// Store is the vector store of the documents.
+	Store vectorstores.VectorStore
+	// Model writes the structured queries.
+	Model llms.Model
+	// DocumentContents describes the contents of the documents.
+	DocumentContents string
+	// Attributes describe the metadata attributes that can be filtered on.
+	Attributes []AttributeInfo
+	// Translator translates the filters. If nil, filters are passed to the store
+	// untranslated.
+	Translator vectorstores.FilterTranslator
+	// NumDocuments is the maximum number of documents returned.
+	NumDocuments int
+	// SearchOptions are the options of the vector store searches.
+	SearchOptions []vectorstores.Option
+	// CallOptions are the options of the model calls.
+	CallOptions []llms.CallOption
+}
+
+var _ schema.Retriever = SelfQueryRetriever{}
+
+// NewSelfQueryRetriever creates a new self-querying retriever of the documents of
+// the store, with the given contents and metadata attributes.
+func NewSelfQueryRetriever(
+	store vectorstores.VectorStore,
+	model llms.Model,
+	documentContents string,
+	attributes []AttributeInfo,
+	translator vectorstores.FilterTranslator,
+	opts ...Option,
+) SelfQueryRetriever {
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return SelfQueryRetriever{
+		Store:            store,
+		Model:            model,
+		DocumentContents: documentContents,
+		Attributes:       attributes,
+		Translator:       translator,
+		NumDocuments:     options.NumDocuments,
+		SearchOptions:    options.SearchOptions,
+		CallOptions:      options.CallOptions,
+	}
+}
+
+// GetRelevantDocuments returns the documents most similar to the text of the
+// structured query of the query that match its filter. If the structured query
+// has no text, the query itself is searched for.
+func (r SelfQueryRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
+	}
+
+	structured, err := r.StructuredQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	searchOptions := r.SearchOptions
+	if structured.Filter != nil {
+		var filters any = structured.Filter
+		if r.Translator != nil {
+			filters, err = r.Translator(structured.Filter)
+			if err != nil {
+				return nil, err
+			}
+		}
+		searchOptions = append(append([]vectorstores.Option{}, r.SearchOptions...), vectorstores.WithFilters(filters))
+	}
+	if structured.Query == "" {
+		structured.Query = query
+	}
+
+	numDocuments := r.NumDocuments
+	if numDocuments <= 0 {
+		numDocuments = _defaultNumDocuments
+	}
+	retriever := vectorstores.ToRetriever(r.Store, numDocuments, searchOptions...)
+	docs, err := retrieveSubQuery(ctx, r.CallbacksHandler, retriever, structured.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CallbacksHandler != nil {
+		r.CallbacksHandler.HandleRetrieverEnd(ctx, query, docs)
+	}
+
+	return docs, nil
+}
+
+// StructuredQuery asks the model for the structured query of the query. It
+// returns ErrInvalidStructuredQuery if the filter uses attributes that are not
+// described.
+func (r SelfQueryRetriever) StructuredQuery(ctx context.Context, query string) (StructuredQuery, error) {
+	attributes := make([]string, len(r.Attributes))
+	for i, attribute := range r.Attributes {
+		attributes[i] = fmt.Sprintf("- %s (%s): %s", attribute.Name, attribute.Type, attribute.Description)
+	}
+	prompt := fmt.Sprintf(_selfQueryPrompt, r.DocumentContents, strings.Join(attributes, "\n"), query)
+	completion, err := llms.GenerateFromSinglePrompt(ctx, r.Model, prompt, r.CallOptions...)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+
+	structured, err := ParseStructuredQuery(completion)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+	if structured.Filter != nil {
+		if err := r.checkAttributes(structured.Filter); err != nil {
+			return StructuredQuery{}, err
+		}
+	}
+	return structured, nil
+}
+
+func (r SelfQueryRetriever) checkAttributes(filter vectorstores.Filter) error {
+	switch f := filter.(type) {
+	case vectorstores.Comparison:
+		for _, attribute := range r.Attributes {
+			if attribute.Name == f.Attribute {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: unknown attribute %q", ErrInvalidStructuredQuery, f.Attribute)
+	case vectorstores.Operation:
+		for _, argument := range f.Arguments {
+			if err := r.checkAttributes(argument); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ParseStructuredQuery parses the JSON structured query of a completion, as
+// asked for by the prompt of SelfQueryRetriever. Text around the JSON object,
+// such as a Markdown code fence, is ignored. Integer values are parsed as int64
+// and other numbers as float64.
+func ParseStructuredQuery(completion string) (StructuredQuery, error) {
+	start, end := strings.Index(completion, "{"), strings.LastIndex(completion, "}")
+	if start < 0 || end < start {
+		return StructuredQuery{}, fmt.Errorf("%w: no JSON object in %q", ErrInvalidStructuredQuery, completion)
+	}
+
+	var raw struct {
+		Query  string          `json:"query"`
+		Filter json.RawMessage `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(completion[start:end+1]), &raw); err != nil {
+		return StructuredQuery{}, fmt.Errorf("%w: %w", ErrInvalidStructuredQuery, err)
+	}
+
+	structured := StructuredQuery{Query: strings.TrimSpace(raw.Query)}
+	if len(raw.Filter) == 0 || string(raw.Filter) == "null" {
+		return structured, nil
+	}
+	filter, err := parseFilter(raw.Filter)
+	if err != nil {
+		return StructuredQuery{}, err
+	}
+	if err := vectorstores.ValidateFilter(filter); err != nil {
+		return StructuredQuery{}, fmt.Errorf("%w: %w", ErrInvalidStructuredQuery, err)
+	}
+	structured.Filter = filter
+	return structured, nil
+}
+
+func parseFilter(data json.RawMessage) (vectorstores.Filter, error) {
+	var raw struct {
+		Operator   string            `json:"operator"`
+		Arguments  []json.RawMessage `json:"arguments"`
+		Comparator string            `json:"comparator"`
+		Attribute  string            `json:"attribute"`
+		Value      any               `json:"value"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidStructuredQuery, err)
+	}
+
+	if raw.Operator == "" {
+		return vectorstores.Comparison{
+			Comparator: vectorstores.Comparator(strings.ToLower(raw.Comparator)),
+			Attribute:  raw.Attribute,
+			Value:      filterValue(raw.Value),
+		}, nil
+	}
+	operation := vectorstores.Operation{
+		Operator:  vectorstores.Operator(strings.ToLower(raw.Operator)),
+		Arguments: make([]vectorstores.Filter, len(raw.Arguments)),
+	}
+	for i, argument := range raw.Arguments {
+		filter, err := parseFilter(argument)
+		if err != nil {
+			return nil, err
+		}
+		operation.Arguments[i] = filter
+	}
+	return operation, nil
+}
+
+// filterValue turns the JSON numbers of a value into int64 or float64 values.
+func filterValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		values := make([]any, len(v))
+		for i, element := range v {
+			values[i] = filterValue(element)
+		}
+		return values
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package retrievers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/retrievers"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/qdrant"
+)
+
+//nolint:gochecknoglobals
+var reportAttributes = []retrievers.AttributeInfo{
+	{Name: "date", Type: "date (ISO 8601 string)", Description: "the publication date of the report"},
+	{Name: "pages", Type: "integer", Description: "the number of pages of the report"},
+}
+
+func TestSelfQueryRetriever(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := &wordStore{}
+	_, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "pricing of the plans"}})
+	require.NoError(t, err)
+	llm := &funcLLM{respond: func(string) string {
+		return "```json\n" + `{"query": "pricing", "filter": {"operator": "and", "arguments": [
+			{"comparator": "gte", "attribute": "date", "value": "2023-01-01"},
+			{"comparator": "lt", "attribute": "pages", "value": 10}]}}` + "\n```"
+	}}
+	handler := &retrieverEvents{}
+	r := retrievers.NewSelfQueryRetriever(store, llm, "quarterly reports", reportAttributes, qdrant.TranslateFilter)
+	r.CallbacksHandler = handler
+
+	docs, err := r.GetRelevantDocuments(ctx, "reports from 2023 about pricing shorter than 10 pages")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pricing of the plans"}, contents(docs))
+	assert.Equal(t, []string{"pricing"}, store.searches)
+	assert.Equal(t, []any{map[string]any{"must": []any{
+		map[string]any{"key": "date", "range": map[string]any{"gte": "2023-01-01T00:00:00Z"}},
+		map[string]any{"key": "pages", "range": map[string]any{"lt": int64(10)}},
+	}}}, store.filters)
+	assert.Equal(t, []string{"reports from 2023 about pricing shorter than 10 pages", "pricing"}, handler.starts)
+	assert.Contains(t, llm.prompts[0], "- pages (integer): the number of pages of the report")
+
+	// a filter on an attribute that is not described is rejected.
+	llm.respond = func(string) string {
+		return `{"query": "pricing", "filter": {"comparator": "eq", "attribute": "author", "value": "bob"}}`
+	}
+	_, err = r.GetRelevantDocuments(ctx, "pricing reports by bob")
+	require.ErrorIs(t, err, retrievers.ErrInvalidStructuredQuery)
+}
+
+func TestParseStructuredQuery(t *testing.T) {
+	t.Parallel()
+
+	structured, err := retrievers.ParseStructuredQuery(`{"query": "", "filter": {"comparator": "IN",
+		"attribute": "pages", "value": [1, 2.5]}}`)
+	require.NoError(t, err)
+	assert.Equal(t, retrievers.StructuredQuery{Filter: vectorstores.Comparison{
+		Comparator: vectorstores.ComparatorIn, Attribute: "pages", Value: []any{int64(1), 2.5},
+	}}, structured)
+
+	structured, err = retrievers.ParseStructuredQuery(`{"query": "pricing", "filter": null}`)
+	require.NoError(t, err)
+	assert.Equal(t, retrievers.StructuredQuery{Query: "pricing"}, structured)
+
+	_, err = retrievers.ParseStructuredQuery(`{"query": "pricing", "filter": {"operator": "xor", "arguments": []}}`)
+	require.ErrorIs(t, err, retrievers.ErrInvalidStructuredQuery)
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = retrievers.ParseStructuredQuery("no structured query")
+	require.ErrorIs(t, err, retrievers.ErrInvalidStructuredQuery)
+}
@@ -2,16 +2,16 @@ package schema
 
 // AgentAction is the agent's action to take.
 type AgentAction struct {
-	Tool      string
-	ToolInput string
-	Log       string
-	ToolID    string
+	Tool      string `json:"tool"`
+	ToolInput string `json:"tool_input"`
+	Log       string `json:"log"`
+	ToolID    string `json:"tool_id,omitempty"`
 }
 
 // AgentStep is a step of the agent.
 type AgentStep struct {
-	Action      AgentAction
-	Observation string
+	Action      AgentAction `json:"action"`
+	Observation string      `json:"observation"`
 }
 
 // AgentFinish is the agent's return value.
@@ -0,0 +1,383 @@
+package textsplitter
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// ErrUnsupportedLanguage is returned by the code splitter for languages it has no
+// separators for.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// Language is a programming language the code splitter can split.
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageJavaScript Language = "javascript"
+	LanguageTypeScript Language = "typescript"
+	LanguageJava       Language = "java"
+	LanguageRust       Language = "rust"
+)
+
+// LanguageSeparators returns the separators used to split code of the language,
+// from the most to the least significant.
+func LanguageSeparators(language Language) ([]string, error) {
+	switch language {
+	case LanguageGo:
+		return []string{
+			"\nfunc ", "\nvar ", "\nconst ", "\ntype ",
+			"\nif ", "\nfor ", "\nswitch ", "\ncase ",
+			"\n\n", "\n", " ", "",
+		}, nil
+	case LanguagePython:
+		return []string{
+			"\nclass ", "\ndef ", "\n\tdef ", "\n    def ",
+			"\n\n", "\n", " ", "",
+		}, nil
+	case LanguageJavaScript:
+		return []string{
+			"\nexport ", "\nfunction ", "\nconst ", "\nlet ", "\nvar ", "\nclass ",
+			"\nif ", "\nfor ", "\nwhile ", "\nswitch ", "\ncase ", "\ndefault ",
+			"\n\n", "\n", " ", "",
+		}, nil
+	case LanguageTypeScript:
+		return []string{
+			"\nexport ", "\nenum ", "\ninterface ", "\nnamespace ", "\ntype ",
+			"\nclass ", "\nfunction ", "\nconst ", "\nlet ", "\nvar ",
+			"\nif ", "\nfor ", "\nwhile ", "\nswitch ", "\ncase ", "\ndefault ",
+			"\n\n", "\n", " ", "",
+		}, nil
+	case LanguageJava:
+		return []string{
+			"\nclass ", "\ninterface ", "\nenum ",
+			"\npublic ", "\nprotected ", "\nprivate ", "\nstatic ",
+			"\nif ", "\nfor ", "\nwhile ", "\nswitch ", "\ncase ",
+			"\n\n", "\n", " ", "",
+		}, nil
+	case LanguageRust:
+		return []string{
+			"\nfn ", "\npub fn ", "\nstruct ", "\npub struct ", "\nenum ", "\npub enum ",
+			"\ntrait ", "\npub trait ", "\nimpl ", "\nmod ", "\nconst ", "\nlet ",
+			"\nif ", "\nwhile ", "\nfor ", "\nloop ", "\nmatch ",
+			"\n\n", "\n", " ", "",
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, language)
+	}
+}
+
+// symbolPattern finds the symbol a chunk of code declares. The submatch named
+// "name" is the symbol and the submatch named "kind" its kind, unless kind is set.
+type symbolPattern struct {
+	re   *regexp.Regexp
+	kind string
+}
+
+var symbolPatterns = map[Language][]symbolPattern{ //nolint:gochecknoglobals
+	LanguagePython: {
+		{re: regexp.MustCompile(`(?m)^\s*(?:async\s+)?(?P<kind>def|class)\s+(?P<name>\w+)`)},
+	},
+	LanguageJavaScript: {
+		{re: regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?(?P<kind>function|class)\*?\s+(?P<name>[\w$]+)`)},                //nolint:lll
+		{re: regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:const|let|var)\s+(?P<name>[\w$]+)\s*=\s*(?:async\s*)?(?:function|\()`), kind: "function"}, //nolint:lll
+		{re: regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?P<kind>const|let|var)\s+(?P<name>[\w$]+)`)},
+	},
+	LanguageTypeScript: {
+		{re: regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(?P<kind>function|class|interface|enum|type|namespace)\*?\s+(?P<name>[\w$]+)`)}, //nolint:lll
+		{re: regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?:const|let|var)\s+(?P<name>[\w$]+)\s*(?::[^=]+)?=\s*(?:async\s*)?(?:function|\()`), kind: "function"},                                    //nolint:lll
+		{re: regexp.MustCompile(`(?m)^\s*(?:export\s+)?(?P<kind>const|let|var)\s+(?P<name>[\w$]+)`)},
+	},
+	LanguageJava: {
+		{re: regexp.MustCompile(`(?m)^\s*(?:(?:public|protected|private|static|final|abstract|sealed)\s+)*(?P<kind>class|interface|enum|record)\s+(?P<name>\w+)`)},                   //nolint:lll
+		{re: regexp.MustCompile(`(?m)^\s*(?:(?:public|protected|private|static|final|abstract|synchronized|default)\s+)*[\w<>\[\],.?]+\s+(?P<name>\w+)\s*\([^;]*$`), kind: "method"}, //nolint:lll
+	},
+	LanguageRust: {
+		{re: regexp.MustCompile(`(?m)^\s*(?:pub(?:\([^)]*\))?\s+)?(?:async\s+)?(?:unsafe\s+)?(?:extern\s+"\w+"\s+)?(?P<kind>fn|struct|enum|trait|mod|const|static|type|macro_rules!)\s*(?P<name>\w+)`)}, //nolint:lll
+		{re: regexp.MustCompile(`(?m)^\s*(?P<kind>impl)(?:<[^>]*>)?\s+(?:[\w:<>, ]+\s+for\s+)?(?P<name>\w+)`)},
+	},
+}
+
+// symbolKinds maps the keywords of the symbol patterns to kinds.
+var symbolKinds = map[string]string{ //nolint:gochecknoglobals
+	"def":          "function",
+	"fn":           "function",
+	"let":          "variable",
+	"var":          "variable",
+	"static":       "variable",
+	"macro_rules!": "macro",
+}
+
+// CodeSplitter is a text splitter for source code. Go code is parsed and split into
+// its top-level declarations, with the doc comments and any other text preceding a
+// declaration kept in its chunk. Code in other languages, or Go code that does not
+// parse, is split with the separators of the language. Declarations longer than the
+// chunk size are split further with the separators of the language.
+//
+// SplitTextWithMetadata returns the "language" and the "start_line" and "end_line" of
+// every chunk, and the "symbol" it declares and its "kind", such as "function" or
+// "type", when known.
+type CodeSplitter struct {
+	Language     Language
+	ChunkSize    int
+	ChunkOverlap int
+	LenFunc      func(string) int
+}
+
+var _ MetadataSplitter = CodeSplitter{}
+
+// NewCodeSplitter creates a new code splitter for the language.
+func NewCodeSplitter(language Language, opts ...Option) CodeSplitter {
+	options := DefaultOptions()
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return CodeSplitter{
+		Language:     language,
+		ChunkSize:    options.ChunkSize,
+		ChunkOverlap: options.ChunkOverlap,
+		LenFunc:      options.LenFunc,
+	}
+}
+
+// SplitText splits code into multiple chunks.
+func (s CodeSplitter) SplitText(text string) ([]string, error) {
+	chunks, _, err := s.SplitTextWithMetadata(text)
+	return chunks, err
+}
+
+// SplitTextWithMetadata splits code into multiple chunks and returns the location and
+// the symbol of every chunk.
+func (s CodeSplitter) SplitTextWithMetadata(text string) ([]string, []map[string]any, error) {
+	separators, err := LanguageSeparators(s.Language)
+	if err != nil {
+		return nil, nil, err
+	}
+	fallback := NewRecursiveCharacter(
+		WithSeparators(separators),
+		WithChunkSize(s.ChunkSize),
+		WithChunkOverlap(s.ChunkOverlap),
+		WithLenFunc(s.LenFunc),
+		WithKeepSeparator(true),
+	)
+
+	if s.Language == LanguageGo {
+		if decls, ok := goDeclarations(text); ok {
+			return s.splitDeclarations(text, decls, fallback)
+		}
+	}
+
+	chunks, err := fallback.SplitText(text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadatas := make([]map[string]any, len(chunks))
+	for i, offsets := range locateChunks(text, chunks) {
+		metadatas[i] = s.chunkMetadata(text, chunks[i], offsets[0])
+		if symbol, kind := s.findSymbol(chunks[i]); symbol != "" {
+			metadatas[i]["symbol"] = symbol
+			metadatas[i]["kind"] = kind
+		}
+	}
+
+	return chunks, metadatas, nil
+}
+
+func (s CodeSplitter) splitDeclarations(
+	text string,
+	decls []goDeclaration,
+	fallback TextSplitter,
+) ([]string, []map[string]any, error) {
+	chunks := make([]string, 0, len(decls))
+	metadatas := make([]map[string]any, 0, len(decls))
+
+	for _, decl := range decls {
+		parts := []string{decl.text}
+		if s.ChunkSize > 0 && s.LenFunc(decl.text) > s.ChunkSize {
+			var err error
+			parts, err = fallback.SplitText(decl.text)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		for i, offsets := range locateChunks(decl.text, parts) {
+			start := -1
+			if offsets[0] >= 0 {
+				start = decl.offset + offsets[0]
+			}
+			metadata := s.chunkMetadata(text, parts[i], start)
+			if decl.symbol != "" {
+				metadata["symbol"] = decl.symbol
+			}
+			metadata["kind"] = decl.kind
+
+			chunks = append(chunks, parts[i])
+			metadatas = append(metadatas, metadata)
+		}
+	}
+
+	return chunks, metadatas, nil
+}
+
+// chunkMetadata returns the language and the line range of a chunk starting at the
+// byte offset start of the text. The line range is omitted if the offset is unknown.
+func (s CodeSplitter) chunkMetadata(text, chunk string, start int) map[string]any {
+	metadata := map[string]any{"language": string(s.Language)}
+	if start < 0 {
+		return metadata
+	}
+
+	startLine := strings.Count(text[:start], "\n") + 1
+	metadata["start_line"] = startLine
+	metadata["end_line"] = startLine + strings.Count(strings.TrimRight(chunk, "\n"), "\n")
+	return metadata
+}
+
+// findSymbol returns the first symbol declared in a chunk of code and its kind.
+func (s CodeSplitter) findSymbol(chunk string) (string, string) {
+	type match struct {
+		at           int
+		symbol, kind string
+	}
+	var first *match
+
+	for _, pattern := range symbolPatterns[s.Language] {
+		loc := pattern.re.FindStringSubmatchIndex(chunk)
+		if loc == nil || (first != nil && loc[0] >= first.at) {
+			continue
+		}
+
+		m := &match{at: loc[0], kind: pattern.kind}
+		for i, name := range pattern.re.SubexpNames() {
+			if loc[2*i] < 0 {
+				continue
+			}
+			switch name {
+			case "name":
+				m.symbol = chunk[loc[2*i]:loc[2*i+1]]
+			case "kind":
+				m.kind = chunk[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		if kind, ok := symbolKinds[m.kind]; ok {
+			m.kind = kind
+		}
+		first = m
+	}
+
+	if first == nil {
+		return "", ""
+	}
+	return first.symbol, first.kind
+}
+
+// goDeclaration is a top-level declaration of a Go file with the text preceding it.
+type goDeclaration struct {
+	text   string
+	offset int
+	symbol string
+	kind   string
+}
+
+// goDeclarations parses a Go file and returns its top-level declarations. The package
+// clause and the imports form the first declaration, and text after the last
+// declaration is part of it. It returns false if the file can not be parsed.
+func goDeclarations(text string) ([]goDeclaration, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", text, parser.ParseComments)
+	if err != nil {
+		return nil, false
+	}
+	tokenFile := fset.File(file.Package)
+
+	var decls []goDeclaration
+	start := 0
+	add := func(end token.Pos, symbol, kind string) {
+		endOffset := tokenFile.Offset(end)
+		raw := text[start:endOffset]
+		trimmed := strings.TrimLeft(raw, " \t\r\n")
+		offset := start + len(raw) - len(trimmed)
+		decls = append(decls, goDeclaration{
+			text:   strings.TrimRight(trimmed, " \t\r\n"),
+			offset: offset,
+			symbol: symbol,
+			kind:   kind,
+		})
+		start = endOffset
+	}
+
+	headerEnd := file.Name.End()
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			headerEnd = gen.End()
+		}
+	}
+	add(headerEnd, file.Name.Name, "package")
+
+	for _, decl := range file.Decls {
+		if decl.End() <= headerEnd {
+			continue
+		}
+		symbol, kind := goDeclSymbol(decl)
+		add(decl.End(), symbol, kind)
+	}
+
+	if rest := strings.TrimSpace(text[start:]); rest != "" && len(decls) > 0 {
+		last := &decls[len(decls)-1]
+		last.text = strings.TrimRight(text[last.offset:], " \t\r\n")
+	}
+
+	return decls, true
+}
+
+// goDeclSymbol returns the name and the kind of a declaration. Methods are named
+// after their receiver type, like "Type.Method", and declarations of several names
+// list them separated by commas.
+func goDeclSymbol(decl ast.Decl) (string, string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return d.Name.Name, "function"
+		}
+		return receiverTypeName(d.Recv.List[0].Type) + "." + d.Name.Name, "method"
+	case *ast.GenDecl:
+		var names []string
+		for _, spec := range d.Specs {
+			switch sp := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, sp.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range sp.Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		return strings.Join(names, ", "), d.Tok.String()
+	default:
+		return "", "declaration"
+	}
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,161 @@
+package textsplitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _goSource = `// Package shapes has shapes.
+package shapes
+
+import "math"
+
+// Pi is pi.
+const Pi = math.Pi
+
+// Circle is a circle.
+type Circle struct {
+	R float64
+}
+
+// Area returns the area of the circle.
+func (c *Circle) Area() float64 {
+	return Pi * c.R * c.R
+}
+
+var a, b = 1, 2
+
+func New(r float64) *Circle { return &Circle{R: r} }
+
+// trailing comment
+`
+
+func TestCodeSplitterGo(t *testing.T) {
+	t.Parallel()
+
+	splitter := NewCodeSplitter(LanguageGo)
+	chunks, metadatas, err := splitter.SplitTextWithMetadata(_goSource)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"// Package shapes has shapes.\npackage shapes\n\nimport \"math\"",
+		"// Pi is pi.\nconst Pi = math.Pi",
+		"// Circle is a circle.\ntype Circle struct {\n\tR float64\n}",
+		"// Area returns the area of the circle.\nfunc (c *Circle) Area() float64 {\n\treturn Pi * c.R * c.R\n}",
+		"var a, b = 1, 2",
+		"func New(r float64) *Circle { return &Circle{R: r} }\n\n// trailing comment",
+	}, chunks)
+
+	assert.Equal(t, []map[string]any{
+		{"language": "go", "symbol": "shapes", "kind": "package", "start_line": 1, "end_line": 4},
+		{"language": "go", "symbol": "Pi", "kind": "const", "start_line": 6, "end_line": 7},
+		{"language": "go", "symbol": "Circle", "kind": "type", "start_line": 9, "end_line": 12},
+		{"language": "go", "symbol": "Circle.Area", "kind": "method", "start_line": 14, "end_line": 17},
+		{"language": "go", "symbol": "a, b", "kind": "var", "start_line": 19, "end_line": 19},
+		{"language": "go", "symbol": "New", "kind": "function", "start_line": 21, "end_line": 23},
+	}, metadatas)
+}
+
+func TestCodeSplitterGoLongDeclaration(t *testing.T) {
+	t.Parallel()
+
+	source := "package main\n\nfunc main() {\n\tfor i := 0; i < 3; i++ {\n\t\tprintln(i)\n\t}\n\n\tprintln(\"done\")\n}\n"
+	splitter := NewCodeSplitter(LanguageGo, WithChunkSize(40), WithChunkOverlap(0))
+	chunks, metadatas, err := splitter.SplitTextWithMetadata(source)
+	require.NoError(t, err)
+
+	require.Greater(t, len(chunks), 2)
+	for i, chunk := range chunks[1:] {
+		assert.LessOrEqual(t, len(chunk), 40, chunk)
+		assert.Equal(t, "main", metadatas[i+1]["symbol"])
+		assert.Equal(t, "function", metadatas[i+1]["kind"])
+	}
+	assert.Equal(t, 3, metadatas[1]["start_line"])
+	assert.Equal(t, 9, metadatas[len(metadatas)-1]["end_line"])
+}
+
+func TestCodeSplitterGoInvalid(t *testing.T) {
+	t.Parallel()
+
+	chunks, metadatas, err := NewCodeSplitter(LanguageGo).SplitTextWithMetadata("func broken( {\n}")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"func broken( {\n}"}, chunks)
+	assert.Equal(t, []map[string]any{
+		{"language": "go", "start_line": 1, "end_line": 2},
+	}, metadatas)
+}
+
+func TestCodeSplitterLanguages(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		language  Language
+		chunkSize int
+		source    string
+		symbols   []string
+		kinds     []string
+	}{
+		{
+			language:  LanguagePython,
+			chunkSize: 60,
+			source:    "import os\n\nclass Greeter:\n    def greet(self):\n        return 'hi'\n\ndef main():\n    print(Greeter().greet())\n",
+			symbols:   []string{"", "Greeter", "main"},
+			kinds:     []string{"", "class", "function"},
+		},
+		{
+			language:  LanguageTypeScript,
+			chunkSize: 80,
+			source:    "interface User {\n  name: string;\n}\n\nexport const greet = (u: User): string => {\n  return u.name;\n};\n",
+			symbols:   []string{"User", "greet"},
+			kinds:     []string{"interface", "function"},
+		},
+		{
+			language:  LanguageRust,
+			chunkSize: 60,
+			source:    "use std::fmt;\n\npub struct Point {\n    x: i32,\n}\n\nfn main() {\n    println!(\"hi\");\n}\n",
+			symbols:   []string{"Point", "main"},
+			kinds:     []string{"struct", "function"},
+		},
+	}
+
+	for _, c := range cases {
+		splitter := NewCodeSplitter(c.language, WithChunkSize(c.chunkSize), WithChunkOverlap(0))
+		chunks, metadatas, err := splitter.SplitTextWithMetadata(c.source)
+		require.NoError(t, err, c.language)
+		require.Len(t, chunks, len(c.symbols), "%s: %q", c.language, chunks)
+
+		for i := range chunks {
+			assert.Equal(t, string(c.language), metadatas[i]["language"])
+			if c.symbols[i] == "" {
+				assert.NotContains(t, metadatas[i], "symbol", "%s: %q", c.language, chunks[i])
+				continue
+			}
+			assert.Equal(t, c.symbols[i], metadatas[i]["symbol"], "%s: %q", c.language, chunks[i])
+			assert.Equal(t, c.kinds[i], metadatas[i]["kind"], "%s: %q", c.language, chunks[i])
+		}
+	}
+
+	_, err := NewCodeSplitter("cobol").SplitText("DISPLAY 'HI'.")
+	require.ErrorIs(t, err, ErrUnsupportedLanguage)
+}
+
+func TestCodeSplitterDocuments(t *testing.T) {
+	t.Parallel()
+
+	docs, err := SplitDocuments(NewCodeSplitter(LanguageGo), []schema.Document{
+		{PageContent: "package a\n\nfunc F() {}\n", Metadata: map[string]any{"source": "a.go"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, map[string]any{
+		"source":     "a.go",
+		"language":   "go",
+		"symbol":     "F",
+		"kind":       "function",
+		"start_line": 3,
+		"end_line":   3,
+	}, docs[1].Metadata)
+}
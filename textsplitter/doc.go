@@ -9,6 +9,9 @@ The main components of this package are:
 - TextSplitter interface: a common interface for splitting texts into smaller chunks.
 - RecursiveCharacter: a text splitter that recursively splits texts by different characters (separators)
 combined with chunk size and overlap settings.
+- HTMLTextSplitter: a text splitter that splits HTML at its headings and sections and records the header hierarchy.
+- CodeSplitter: a text splitter for source code that keeps declarations whole and records their location.
+- SemanticSplitter: a text splitter that uses embeddings to keep sentences about the same topic together.
 - Helper functions: utility functions for creating documents out of split texts and rejoining them if necessary.
 
 Using the TextSplitter interface, developers can implement custom
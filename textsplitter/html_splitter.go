@@ -0,0 +1,347 @@
+package textsplitter
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// NewHTMLTextSplitter creates a new HTML text splitter.
+func NewHTMLTextSplitter(opts ...Option) *HTMLTextSplitter {
+	options := DefaultOptions()
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	sp := &HTMLTextSplitter{
+		ChunkSize:        options.ChunkSize,
+		ChunkOverlap:     options.ChunkOverlap,
+		SecondSplitter:   options.SecondSplitter,
+		CodeBlocks:       options.CodeBlocks,
+		HeadingHierarchy: options.KeepHeadingHierarchy,
+		LenFunc:          options.LenFunc,
+	}
+
+	if sp.SecondSplitter == nil {
+		sp.SecondSplitter = NewRecursiveCharacter(
+			WithChunkSize(options.ChunkSize),
+			WithChunkOverlap(options.ChunkOverlap),
+			WithLenFunc(options.LenFunc),
+			WithSeparators([]string{
+				"\n\n", // new line
+				"\n",   // new line
+				" ",    // space
+			}),
+		)
+	}
+
+	return sp
+}
+
+var _ MetadataSplitter = (*HTMLTextSplitter)(nil)
+
+// HTMLTextSplitter splits HTML along its structure. A new chunk is started at every
+// h1 to h6 heading and at the start and end of every section and article. Chunks are
+// prefixed with the heading of their section, or with all the headings above it if
+// HeadingHierarchy is set, like with the MarkdownTextSplitter. Tables, and code
+// blocks if CodeBlocks is set, are never split, even if they are longer than the
+// chunk size. Other paragraphs that do not fit in a chunk are split with
+// SecondSplitter.
+//
+// SplitTextWithMetadata returns the "headers" of every chunk, the titles of the
+// headings it is under from the outermost to the innermost.
+type HTMLTextSplitter struct {
+	ChunkSize    int
+	ChunkOverlap int
+	// SecondSplitter splits paragraphs
+	SecondSplitter   TextSplitter
+	CodeBlocks       bool
+	HeadingHierarchy bool
+	LenFunc          func(string) int
+}
+
+// SplitText splits HTML into multiple texts.
+func (sp HTMLTextSplitter) SplitText(text string) ([]string, error) {
+	chunks, _, err := sp.SplitTextWithMetadata(text)
+	return chunks, err
+}
+
+// SplitTextWithMetadata splits HTML into multiple texts and returns the headers of
+// every chunk.
+func (sp HTMLTextSplitter) SplitTextWithMetadata(text string) ([]string, []map[string]any, error) {
+	root, err := html.Parse(strings.NewReader(text))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hc := &htmlContext{splitter: sp}
+	hc.walk(root)
+	hc.onSection()
+	if hc.err != nil {
+		return nil, nil, hc.err
+	}
+
+	return hc.chunks, hc.metadatas, nil
+}
+
+// htmlHeading is a heading of the document.
+type htmlHeading struct {
+	level int
+	title string
+}
+
+// htmlBlock is a paragraph, table or code block of a section. Whole blocks are
+// never split.
+type htmlBlock struct {
+	text  string
+	whole bool
+}
+
+// htmlContext is the state of splitting an HTML document.
+type htmlContext struct {
+	splitter HTMLTextSplitter
+
+	// headings is the hierarchy of headings above the current section
+	headings []htmlHeading
+	// blocks are the blocks of the current section
+	blocks []htmlBlock
+	// titleApplied represents whether the current heading has been applied to chunks
+	titleApplied bool
+	// paragraph is the text of the current paragraph
+	paragraph strings.Builder
+
+	chunks    []string
+	metadatas []map[string]any
+	// err is the first error of the second splitter
+	err error
+}
+
+//nolint:cyclop
+func (hc *htmlContext) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		hc.paragraph.WriteString(n.Data)
+		return
+	case html.ElementNode, html.DocumentNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom { //nolint:exhaustive
+	case atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template:
+		return
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		hc.onHeading(n)
+		return
+	case atom.Table:
+		hc.endParagraph()
+		hc.addBlock(htmlBlock{text: htmlTableText(n), whole: true})
+		return
+	case atom.Pre:
+		hc.endParagraph()
+		if hc.splitter.CodeBlocks {
+			hc.addBlock(htmlBlock{text: fmt.Sprintf("```\n%s\n```", strings.Trim(htmlNodeText(n), "\n")), whole: true})
+		}
+		return
+	case atom.Section, atom.Article:
+		hc.onSection()
+		defer hc.onSection()
+	case atom.Li:
+		hc.paragraph.WriteString("\n- ")
+	case atom.Br:
+		hc.paragraph.WriteString("\n")
+	}
+
+	block := htmlIsBlock(n.DataAtom)
+	if block {
+		hc.endParagraph()
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		hc.walk(c)
+	}
+	if block {
+		hc.endParagraph()
+	}
+}
+
+// onHeading starts a new section under the heading.
+func (hc *htmlContext) onHeading(n *html.Node) {
+	hc.onSection()
+
+	level := int(n.Data[1] - '0')
+	for len(hc.headings) > 0 && hc.headings[len(hc.headings)-1].level >= level {
+		hc.headings = hc.headings[:len(hc.headings)-1]
+	}
+	hc.headings = append(hc.headings, htmlHeading{
+		level: level,
+		title: collapseSpaces(htmlNodeText(n)),
+	})
+	hc.titleApplied = false
+}
+
+// onSection ends the current section.
+func (hc *htmlContext) onSection() {
+	hc.endParagraph()
+	if err := hc.applyToChunks(); err != nil && hc.err == nil {
+		hc.err = err
+	}
+}
+
+// endParagraph adds the current paragraph to the blocks of the section.
+func (hc *htmlContext) endParagraph() {
+	lines := strings.Split(hc.paragraph.String(), "\n")
+	hc.paragraph.Reset()
+
+	text := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = collapseSpaces(line); line != "" && line != "-" {
+			text = append(text, line)
+		}
+	}
+	if len(text) > 0 {
+		hc.addBlock(htmlBlock{text: strings.Join(text, "\n")})
+	}
+}
+
+func (hc *htmlContext) addBlock(block htmlBlock) {
+	if strings.TrimSpace(block.text) != "" {
+		hc.blocks = append(hc.blocks, block)
+	}
+}
+
+// title returns the text chunks of the current section are prefixed with.
+func (hc *htmlContext) title() string {
+	if len(hc.headings) == 0 {
+		return ""
+	}
+
+	headings := hc.headings[len(hc.headings)-1:]
+	if hc.splitter.HeadingHierarchy {
+		headings = hc.headings
+	}
+
+	lines := make([]string, 0, len(headings))
+	for _, h := range headings {
+		lines = append(lines, fmt.Sprintf("%s %s", strings.Repeat("#", h.level), h.title))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyToChunks merges the blocks of the current section into chunks.
+func (hc *htmlContext) applyToChunks() error {
+	title := hc.title()
+	blocks := hc.blocks
+	hc.blocks = nil
+
+	headers := make([]string, 0, len(hc.headings))
+	for _, h := range hc.headings {
+		headers = append(headers, h.title)
+	}
+
+	lenFunc := hc.splitter.LenFunc
+	if lenFunc == nil {
+		lenFunc = func(s string) int { return len([]rune(s)) }
+	}
+
+	var texts []string
+	current := ""
+	flush := func() {
+		if current != "" {
+			texts = append(texts, current)
+			current = ""
+		}
+	}
+
+	for _, block := range blocks {
+		if current != "" && lenFunc(current)+lenFunc(block.text)+2 <= hc.splitter.ChunkSize {
+			current += "\n\n" + block.text
+			continue
+		}
+		flush()
+
+		if block.whole || lenFunc(block.text) <= hc.splitter.ChunkSize {
+			current = block.text
+			continue
+		}
+
+		splits, err := hc.splitter.SecondSplitter.SplitText(block.text)
+		if err != nil {
+			return err
+		}
+		texts = append(texts, splits...)
+	}
+	flush()
+
+	// a heading without content is a chunk of its own
+	if len(texts) == 0 && title != "" && !hc.titleApplied {
+		hc.titleApplied = true
+		hc.chunks = append(hc.chunks, title)
+		hc.metadatas = append(hc.metadatas, map[string]any{"headers": headers})
+		return nil
+	}
+
+	for _, text := range texts {
+		hc.titleApplied = true
+		if title != "" {
+			text = title + "\n" + text
+		}
+		hc.chunks = append(hc.chunks, text)
+		hc.metadatas = append(hc.metadatas, map[string]any{"headers": headers})
+	}
+
+	return nil
+}
+
+// htmlTableText renders a table with one line per row and the cells of a row
+// separated by " | ".
+func htmlTableText(table *html.Node) string {
+	var rows []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Tr {
+			var cells []string
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) {
+					cells = append(cells, collapseSpaces(htmlNodeText(c)))
+				}
+			}
+			rows = append(rows, strings.Join(cells, " | "))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(table)
+
+	return strings.Join(rows, "\n")
+}
+
+func htmlNodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(htmlNodeText(c))
+	}
+	return sb.String()
+}
+
+func htmlIsBlock(a atom.Atom) bool {
+	switch a { //nolint:exhaustive
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Aside, atom.Main, atom.Nav,
+		atom.Ul, atom.Ol, atom.Dl, atom.Dt, atom.Dd,
+		atom.Blockquote, atom.Hr, atom.Figure, atom.Figcaption, atom.Form, atom.Fieldset, atom.Details, atom.Summary:
+		return true
+	default:
+		return false
+	}
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
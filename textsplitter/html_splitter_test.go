@@ -0,0 +1,93 @@
+package textsplitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _htmlPage = `<!DOCTYPE html>
+<html>
+<head><title>Guide</title><style>body { color: red; }</style></head>
+<body>
+  <h1>Guide</h1>
+  <p>Welcome to   the guide.</p>
+  <h2>Install</h2>
+  <p>Run the installer.</p>
+  <pre><code>go get example.com/tool
+tool init</code></pre>
+  <h2>Usage</h2>
+  <ul><li>Start it</li><li>Stop it</li></ul>
+  <table>
+    <tr><th>Flag</th><th>Meaning</th></tr>
+    <tr><td>-v</td><td>verbose</td></tr>
+  </table>
+  <section>
+    <p>A note in a section.</p>
+  </section>
+  <h3>Advanced</h3>
+  <h1>Appendix</h1>
+  <p>The end.</p>
+</body>
+</html>`
+
+func TestHTMLTextSplitter(t *testing.T) {
+	t.Parallel()
+
+	splitter := NewHTMLTextSplitter(WithCodeBlocks(true))
+	chunks, metadatas, err := splitter.SplitTextWithMetadata(_htmlPage)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"# Guide\nWelcome to the guide.",
+		"## Install\nRun the installer.\n\n```\ngo get example.com/tool\ntool init\n```",
+		"## Usage\n- Start it\n- Stop it\n\nFlag | Meaning\n-v | verbose",
+		"## Usage\nA note in a section.",
+		"### Advanced",
+		"# Appendix\nThe end.",
+	}, chunks)
+
+	assert.Equal(t, []map[string]any{
+		{"headers": []string{"Guide"}},
+		{"headers": []string{"Guide", "Install"}},
+		{"headers": []string{"Guide", "Usage"}},
+		{"headers": []string{"Guide", "Usage"}},
+		{"headers": []string{"Guide", "Usage", "Advanced"}},
+		{"headers": []string{"Appendix"}},
+	}, metadatas)
+}
+
+func TestHTMLTextSplitterHierarchy(t *testing.T) {
+	t.Parallel()
+
+	splitter := NewHTMLTextSplitter(WithHeadingHierarchy(true))
+	chunks, err := splitter.SplitText(_htmlPage)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"# Guide\nWelcome to the guide.",
+		"# Guide\n## Install\nRun the installer.",
+		"# Guide\n## Usage\n- Start it\n- Stop it\n\nFlag | Meaning\n-v | verbose",
+		"# Guide\n## Usage\nA note in a section.",
+		"# Guide\n## Usage\n### Advanced",
+		"# Appendix\nThe end.",
+	}, chunks)
+}
+
+func TestHTMLTextSplitterChunkSize(t *testing.T) {
+	t.Parallel()
+
+	page := `<h1>Long</h1><p>one two three four five six seven eight</p>` +
+		`<table><tr><td>a table that is longer than the chunk size</td></tr></table>`
+
+	splitter := NewHTMLTextSplitter(WithChunkSize(20), WithChunkOverlap(0))
+	chunks, err := splitter.SplitText(page)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"# Long\none two three four",
+		"# Long\nfive six seven eight",
+		"# Long\na table that is longer than the chunk size",
+	}, chunks)
+}
@@ -1,6 +1,10 @@
 package textsplitter
 
-import "unicode/utf8"
+import (
+	"unicode/utf8"
+
+	"github.com/tmc/langchaingo/llms"
+)
 
 // Options is a struct that contains options for a text splitter.
 type Options struct {
@@ -11,12 +15,16 @@ type Options struct {
 	LenFunc              func(string) int
 	ModelName            string
 	EncodingName         string
+	Tokenizer            llms.TokenEncoder
 	AllowedSpecial       []string
 	DisallowedSpecial    []string
 	SecondSplitter       TextSplitter
 	CodeBlocks           bool
 	ReferenceLinks       bool
 	KeepHeadingHierarchy bool // Persist hierarchy of markdown headers in each chunk
+	BreakpointType       BreakpointType
+	BreakpointThreshold  float64
+	BufferSize           int
 }
 
 // DefaultOptions returns the default options for all text splitter.
@@ -34,6 +42,9 @@ func DefaultOptions() Options {
 		DisallowedSpecial: []string{"all"},
 
 		KeepHeadingHierarchy: false,
+
+		BreakpointType: BreakpointPercentile,
+		BufferSize:     1,
 	}
 }
 
@@ -82,6 +93,14 @@ func WithEncodingName(encodingName string) Option {
 	}
 }
 
+// WithTokenizer sets the tokenizer a token splitter splits texts with, instead of
+// the tokenizer of its encoding or model.
+func WithTokenizer(tokenizer llms.TokenEncoder) Option {
+	return func(o *Options) {
+		o.Tokenizer = tokenizer
+	}
+}
+
 // WithAllowedSpecial sets the allowed special tokens for a text splitter.
 func WithAllowedSpecial(allowedSpecial []string) Option {
 	return func(o *Options) {
@@ -145,3 +164,24 @@ func WithHeadingHierarchy(trackHeadingHierarchy bool) Option {
 		o.KeepHeadingHierarchy = trackHeadingHierarchy
 	}
 }
+
+// WithBreakpoint sets how the semantic splitter decides where to start a new chunk.
+// The threshold is the percentile of the distances between neighbouring sentences
+// for BreakpointPercentile, and the number of standard deviations above the mean
+// distance for BreakpointStandardDeviation. A zero threshold uses the default of
+// the breakpoint type.
+func WithBreakpoint(breakpointType BreakpointType, threshold float64) Option {
+	return func(o *Options) {
+		o.BreakpointType = breakpointType
+		o.BreakpointThreshold = threshold
+	}
+}
+
+// WithBufferSize sets the number of sentences on each side of a sentence that the
+// semantic splitter embeds together with it, to smooth out the distances between
+// short sentences.
+func WithBufferSize(bufferSize int) Option {
+	return func(o *Options) {
+		o.BufferSize = bufferSize
+	}
+}
@@ -0,0 +1,282 @@
+package textsplitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_defaultPercentileThreshold        = 95
+	_defaultStandardDeviationThreshold = 3
+)
+
+// ErrInvalidBreakpointType is returned by the semantic splitter for unknown breakpoint types.
+var ErrInvalidBreakpointType = errors.New("invalid breakpoint type")
+
+// BreakpointType is the way the semantic splitter computes the distance threshold
+// above which a new chunk is started.
+type BreakpointType string
+
+const (
+	// BreakpointPercentile starts a new chunk where the distance is above a percentile
+	// of all the distances between neighbouring sentences.
+	BreakpointPercentile BreakpointType = "percentile"
+	// BreakpointStandardDeviation starts a new chunk where the distance is more than a
+	// number of standard deviations above the mean distance.
+	BreakpointStandardDeviation BreakpointType = "standard_deviation"
+)
+
+// SemanticSplitter is a text splitter that keeps sentences about the same topic
+// together. It embeds every sentence, with BufferSize sentences on each side of it,
+// and starts a new chunk where the cosine distance between the embeddings of
+// neighbouring sentences is above the breakpoint threshold. Chunks are never longer
+// than ChunkSize as measured by LenFunc; sentences longer than that are split with
+// SecondSplitter, or a RecursiveCharacter splitter if it is nil.
+type SemanticSplitter struct {
+	Embedder            embeddings.Embedder
+	BreakpointType      BreakpointType
+	BreakpointThreshold float64
+	BufferSize          int
+	ChunkSize           int
+	LenFunc             func(string) int
+	SecondSplitter      TextSplitter
+}
+
+var _ TextSplitter = SemanticSplitter{}
+
+// NewSemanticSplitter creates a new semantic splitter using the embedder. By default
+// a new chunk is started where the distance is above the 95th percentile.
+func NewSemanticSplitter(embedder embeddings.Embedder, opts ...Option) SemanticSplitter {
+	options := DefaultOptions()
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return SemanticSplitter{
+		Embedder:            embedder,
+		BreakpointType:      options.BreakpointType,
+		BreakpointThreshold: options.BreakpointThreshold,
+		BufferSize:          options.BufferSize,
+		ChunkSize:           options.ChunkSize,
+		LenFunc:             options.LenFunc,
+		SecondSplitter:      options.SecondSplitter,
+	}
+}
+
+// SplitText splits a text into chunks of sentences about the same topic.
+func (s SemanticSplitter) SplitText(text string) ([]string, error) {
+	return s.SplitTextContext(context.Background(), text)
+}
+
+// SplitTextContext splits a text into chunks of sentences about the same topic, using
+// ctx for the calls to the embedder.
+func (s SemanticSplitter) SplitTextContext(ctx context.Context, text string) ([]string, error) {
+	sentences := splitSentences(text)
+	if len(sentences) == 0 {
+		return []string{}, nil
+	}
+
+	threshold, err := s.threshold()
+	if err != nil {
+		return nil, err
+	}
+
+	breakpoints := make([]bool, len(sentences))
+	if len(sentences) > 1 {
+		distances, err := s.distances(ctx, sentences)
+		if err != nil {
+			return nil, err
+		}
+
+		var limit float64
+		switch s.BreakpointType {
+		case BreakpointStandardDeviation:
+			mean, stddev := meanStdDev(distances)
+			limit = mean + threshold*stddev
+		default:
+			limit = percentile(distances, threshold)
+		}
+		for i, distance := range distances {
+			breakpoints[i+1] = distance > limit
+		}
+	}
+
+	return s.mergeSentences(sentences, breakpoints)
+}
+
+func (s SemanticSplitter) threshold() (float64, error) {
+	switch s.BreakpointType {
+	case BreakpointPercentile, "":
+		if s.BreakpointThreshold == 0 {
+			return _defaultPercentileThreshold, nil
+		}
+	case BreakpointStandardDeviation:
+		if s.BreakpointThreshold == 0 {
+			return _defaultStandardDeviationThreshold, nil
+		}
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidBreakpointType, s.BreakpointType)
+	}
+	return s.BreakpointThreshold, nil
+}
+
+// distances returns the cosine distances between the embeddings of each sentence,
+// with its buffer, and the next one.
+func (s SemanticSplitter) distances(ctx context.Context, sentences []string) ([]float64, error) {
+	combined := make([]string, len(sentences))
+	for i := range sentences {
+		start := max(0, i-s.BufferSize)
+		end := min(len(sentences), i+s.BufferSize+1)
+		combined[i] = strings.Join(sentences[start:end], " ")
+	}
+
+	vectors, err := s.Embedder.EmbedDocuments(ctx, combined)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(combined) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d sentences", len(vectors), len(combined))
+	}
+
+	distances := make([]float64, len(vectors)-1)
+	for i := range distances {
+		distances[i] = 1 - cosineSimilarity(vectors[i], vectors[i+1])
+	}
+	return distances, nil
+}
+
+// mergeSentences joins the sentences into chunks, starting a new chunk at every
+// breakpoint and whenever the chunk would get longer than the chunk size.
+func (s SemanticSplitter) mergeSentences(sentences []string, breakpoints []bool) ([]string, error) {
+	lenFunc := s.LenFunc
+	if lenFunc == nil {
+		lenFunc = func(text string) int { return len([]rune(text)) }
+	}
+
+	chunks := make([]string, 0)
+	current := make([]string, 0)
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, " "))
+			current = current[:0]
+		}
+	}
+
+	for i, sentence := range sentences {
+		if breakpoints[i] {
+			flush()
+		}
+
+		if s.ChunkSize > 0 && lenFunc(sentence) > s.ChunkSize {
+			flush()
+			splits, err := s.secondSplitter().SplitText(sentence)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, splits...)
+			continue
+		}
+
+		if s.ChunkSize > 0 && len(current) > 0 &&
+			lenFunc(strings.Join(append(current, sentence), " ")) > s.ChunkSize {
+			flush()
+		}
+		current = append(current, sentence)
+	}
+	flush()
+
+	return chunks, nil
+}
+
+func (s SemanticSplitter) secondSplitter() TextSplitter { //nolint:ireturn
+	if s.SecondSplitter != nil {
+		return s.SecondSplitter
+	}
+
+	opts := []Option{WithChunkSize(s.ChunkSize), WithChunkOverlap(0)}
+	if s.LenFunc != nil {
+		opts = append(opts, WithLenFunc(s.LenFunc))
+	}
+	return NewRecursiveCharacter(opts...)
+}
+
+// splitSentences splits a text after every ".", "?" or "!" followed by white space,
+// and at blank lines.
+func splitSentences(text string) []string {
+	sentences := make([]string, 0)
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		end := false
+		switch {
+		case (runes[i] == '.' || runes[i] == '?' || runes[i] == '!') &&
+			i+1 < len(runes) && unicode.IsSpace(runes[i+1]):
+			end = true
+		case runes[i] == '\n' && i+1 < len(runes) && runes[i+1] == '\n':
+			end = true
+		}
+		if !end {
+			continue
+		}
+
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// percentile returns the p-th percentile of the values, interpolating linearly
+// between the closest ranks.
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	rank = math.Max(0, math.Min(rank, float64(len(sorted)-1)))
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,127 @@
+package textsplitter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// topicEmbedder embeds texts by the topics they mention.
+type topicEmbedder struct {
+	topics []string
+}
+
+func (e topicEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vector, err := e.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
+
+func (e topicEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, len(e.topics))
+	for i, topic := range e.topics {
+		vector[i] = float32(strings.Count(strings.ToLower(text), topic))
+	}
+	return vector, nil
+}
+
+func TestSemanticSplitter(t *testing.T) {
+	t.Parallel()
+
+	embedder := topicEmbedder{topics: []string{"cat", "car"}}
+	text := "Cats purr. A cat sleeps all day. My cat likes fish. " +
+		"Cars need fuel. A car has four wheels. The car is red."
+
+	cases := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name: "percentile",
+			opts: []Option{WithBufferSize(0)},
+			expected: []string{
+				"Cats purr. A cat sleeps all day. My cat likes fish.",
+				"Cars need fuel. A car has four wheels. The car is red.",
+			},
+		},
+		{
+			name: "standard deviation",
+			opts: []Option{WithBufferSize(0), WithBreakpoint(BreakpointStandardDeviation, 1)},
+			expected: []string{
+				"Cats purr. A cat sleeps all day. My cat likes fish.",
+				"Cars need fuel. A car has four wheels. The car is red.",
+			},
+		},
+		{
+			name: "chunk size",
+			opts: []Option{WithBufferSize(0), WithChunkSize(35)},
+			expected: []string{
+				"Cats purr. A cat sleeps all day.",
+				"My cat likes fish.",
+				"Cars need fuel.",
+				"A car has four wheels.",
+				"The car is red.",
+			},
+		},
+		{
+			name: "long sentence",
+			opts: []Option{WithBufferSize(0), WithChunkSize(12)},
+			expected: []string{
+				"Cats purr.",
+				"A cat sleeps", "all day.",
+				"My cat likes", "fish.",
+				"Cars need", "fuel.",
+				"A car has", "four wheels.",
+				"The car is", "red.",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		splitter := NewSemanticSplitter(embedder, c.opts...)
+		chunks, err := splitter.SplitText(text)
+		require.NoError(t, err, c.name)
+		assert.Equal(t, c.expected, chunks, c.name)
+	}
+}
+
+func TestSemanticSplitterEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	splitter := NewSemanticSplitter(topicEmbedder{topics: []string{"cat"}})
+
+	chunks, err := splitter.SplitText("   ")
+	require.NoError(t, err)
+	assert.Empty(t, chunks)
+
+	chunks, err = splitter.SplitText("One sentence only")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"One sentence only"}, chunks)
+
+	splitter.BreakpointType = "unknown"
+	_, err = splitter.SplitText("A cat. A dog.")
+	require.ErrorIs(t, err, ErrInvalidBreakpointType)
+}
+
+func TestSplitSentences(t *testing.T) {
+	t.Parallel()
+
+	sentences := splitSentences("Hello there! How are you? I am fine.\n\nNew paragraph without end\n\nv1.2 is out.")
+	assert.Equal(t, []string{
+		"Hello there!",
+		"How are you?",
+		"I am fine.",
+		"New paragraph without end",
+		"v1.2 is out.",
+	}, sentences)
+}
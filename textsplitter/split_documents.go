@@ -1,9 +1,12 @@
 package textsplitter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"log"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/tmc/langchaingo/schema"
 )
@@ -13,8 +16,63 @@ import (
 // length of the metadatas slice is zero.
 var ErrMismatchMetadatasAndText = errors.New("number of texts and metadatas does not match")
 
+// Metadata keys of the chunk provenance added by WithChunkProvenance.
+const (
+	// MetadataChunkIndex is the index of the chunk among the chunks of its parent.
+	MetadataChunkIndex = "chunk_index"
+	// MetadataChunkTotal is the number of chunks of the parent.
+	MetadataChunkTotal = "chunk_total"
+	// MetadataStartIndex is the character offset of the start of the chunk in the
+	// text of the parent.
+	MetadataStartIndex = "start_index"
+	// MetadataEndIndex is the character offset of the end of the chunk in the text of
+	// the parent.
+	MetadataEndIndex = "end_index"
+	// MetadataParentID is the id of the parent.
+	MetadataParentID = "parent_id"
+	// MetadataContentHash is the hex encoded SHA-256 hash of the content of the chunk.
+	MetadataContentHash = "content_hash"
+)
+
+// ParentIDFunc returns the id of a document that is split into chunks.
+type ParentIDFunc func(text string, metadata map[string]any) string
+
+// DocumentOptions are the options of SplitDocuments and CreateDocuments.
+type DocumentOptions struct {
+	Provenance   bool
+	ParentIDFunc ParentIDFunc
+}
+
+// DocumentOption is a function that can be used to set options for SplitDocuments and
+// CreateDocuments.
+type DocumentOption func(*DocumentOptions)
+
+// WithChunkProvenance adds the provenance of every chunk to its metadata: its index
+// and the number of chunks of its parent, its start and end character offsets in the
+// text of the parent, the id of the parent and a hash of its content. The offsets are
+// left out for chunks that are not part of the text of their parent, such as the
+// chunks of the markdown splitter that are prefixed with their headings.
+func WithChunkProvenance() DocumentOption {
+	return func(o *DocumentOptions) {
+		o.Provenance = true
+	}
+}
+
+// WithParentIDFunc sets the function returning the id of a parent document, such as
+// one derived from its "source" metadata. The default id is the hex encoded SHA-256
+// hash of the text of the parent, which is stable as long as the text does not change.
+func WithParentIDFunc(fn ParentIDFunc) DocumentOption {
+	return func(o *DocumentOptions) {
+		o.ParentIDFunc = fn
+	}
+}
+
 // SplitDocuments splits documents using a textsplitter.
-func SplitDocuments(textSplitter TextSplitter, documents []schema.Document) ([]schema.Document, error) {
+func SplitDocuments(
+	textSplitter TextSplitter,
+	documents []schema.Document,
+	opts ...DocumentOption,
+) ([]schema.Document, error) {
 	texts := make([]string, 0)
 	metadatas := make([]map[string]any, 0)
 	for _, document := range documents {
@@ -22,13 +80,23 @@ func SplitDocuments(textSplitter TextSplitter, documents []schema.Document) ([]s
 		metadatas = append(metadatas, document.Metadata)
 	}
 
-	return CreateDocuments(textSplitter, texts, metadatas)
+	return CreateDocuments(textSplitter, texts, metadatas, opts...)
 }
 
 // CreateDocuments creates documents from texts and metadatas with a text splitter. If
 // the length of the metadatas is zero, the result documents will contain no metadata.
 // Otherwise, the numbers of texts and metadatas must match.
-func CreateDocuments(textSplitter TextSplitter, texts []string, metadatas []map[string]any) ([]schema.Document, error) {
+func CreateDocuments(
+	textSplitter TextSplitter,
+	texts []string,
+	metadatas []map[string]any,
+	opts ...DocumentOption,
+) ([]schema.Document, error) {
+	options := DocumentOptions{ParentIDFunc: contentHashParentID}
+	for _, o := range opts {
+		o(&options)
+	}
+
 	if len(metadatas) == 0 {
 		metadatas = make([]map[string]any, len(texts))
 	}
@@ -40,17 +108,32 @@ func CreateDocuments(textSplitter TextSplitter, texts []string, metadatas []map[
 	documents := make([]schema.Document, 0)
 
 	for i := 0; i < len(texts); i++ {
-		chunks, err := textSplitter.SplitText(texts[i])
+		chunks, chunkMetadatas, err := splitTextWithMetadata(textSplitter, texts[i])
 		if err != nil {
 			return nil, err
 		}
 
-		for _, chunk := range chunks {
+		var provenance []map[string]any
+		if options.Provenance {
+			provenance = chunkProvenance(texts[i], metadatas[i], chunks, options.ParentIDFunc)
+		}
+
+		for j, chunk := range chunks {
 			// Copy the document metadata
 			curMetadata := make(map[string]any, len(metadatas[i]))
 			for key, value := range metadatas[i] {
 				curMetadata[key] = value
 			}
+			if chunkMetadatas != nil {
+				for key, value := range chunkMetadatas[j] {
+					curMetadata[key] = value
+				}
+			}
+			if provenance != nil {
+				for key, value := range provenance[j] {
+					curMetadata[key] = value
+				}
+			}
 
 			documents = append(documents, schema.Document{
 				PageContent: chunk,
@@ -62,6 +145,80 @@ func CreateDocuments(textSplitter TextSplitter, texts []string, metadatas []map[
 	return documents, nil
 }
 
+// chunkProvenance returns the provenance metadata of the chunks of a text.
+func chunkProvenance(
+	text string,
+	metadata map[string]any,
+	chunks []string,
+	parentIDFunc ParentIDFunc,
+) []map[string]any {
+	parentID := parentIDFunc(text, metadata)
+	offsets := locateChunks(text, chunks)
+
+	provenance := make([]map[string]any, len(chunks))
+	for i, chunk := range chunks {
+		hash := sha256.Sum256([]byte(chunk))
+		provenance[i] = map[string]any{
+			MetadataChunkIndex:  i,
+			MetadataChunkTotal:  len(chunks),
+			MetadataParentID:    parentID,
+			MetadataContentHash: hex.EncodeToString(hash[:]),
+		}
+		if start := offsets[i][0]; start >= 0 {
+			// offsets are in characters rather than bytes
+			startIndex := utf8.RuneCountInString(text[:start])
+			provenance[i][MetadataStartIndex] = startIndex
+			provenance[i][MetadataEndIndex] = startIndex + utf8.RuneCountInString(chunk)
+		}
+	}
+	return provenance
+}
+
+func contentHashParentID(text string, _ map[string]any) string {
+	hash := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(hash[:])
+}
+
+// splitTextWithMetadata splits a text, returning the metadata of the chunks if the
+// splitter is a MetadataSplitter.
+func splitTextWithMetadata(textSplitter TextSplitter, text string) ([]string, []map[string]any, error) {
+	metadataSplitter, ok := textSplitter.(MetadataSplitter)
+	if !ok {
+		chunks, err := textSplitter.SplitText(text)
+		return chunks, nil, err
+	}
+
+	chunks, metadatas, err := metadataSplitter.SplitTextWithMetadata(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(chunks) != len(metadatas) {
+		return nil, nil, ErrMismatchMetadatasAndText
+	}
+	return chunks, metadatas, nil
+}
+
+// locateChunks returns the byte offsets of the start and end of every chunk in the
+// text, assuming the chunks are substrings of the text in order, possibly
+// overlapping. Chunks that can not be found have a start offset of -1.
+func locateChunks(text string, chunks []string) [][2]int {
+	offsets := make([][2]int, len(chunks))
+	from := 0
+	for i, chunk := range chunks {
+		idx := strings.Index(text[from:], chunk)
+		if idx < 0 {
+			offsets[i] = [2]int{-1, -1}
+			continue
+		}
+		start := from + idx
+		offsets[i] = [2]int{start, start + len(chunk)}
+		if start < len(text) {
+			from = start + 1
+		}
+	}
+	return offsets
+}
+
 // joinDocs comines two documents with the separator used to split them.
 func joinDocs(docs []string, separator string) string {
 	return strings.TrimSpace(strings.Join(docs, separator))
@@ -0,0 +1,76 @@
+package textsplitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestSplitDocumentsProvenance(t *testing.T) {
+	t.Parallel()
+
+	text := "héllo wörld. foo bar baz. the end"
+	splitter := NewRecursiveCharacter(
+		WithChunkSize(12),
+		WithChunkOverlap(0),
+		WithSeparators([]string{". "}),
+	)
+
+	docs, err := SplitDocuments(splitter, []schema.Document{
+		{PageContent: text, Metadata: map[string]any{"source": "a.txt"}},
+	}, WithChunkProvenance())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	runes := []rune(text)
+	parentID := docs[0].Metadata[MetadataParentID]
+	require.NotEmpty(t, parentID)
+	for i, doc := range docs {
+		assert.Equal(t, "a.txt", doc.Metadata["source"])
+		assert.Equal(t, i, doc.Metadata[MetadataChunkIndex])
+		assert.Equal(t, 3, doc.Metadata[MetadataChunkTotal])
+		assert.Equal(t, parentID, doc.Metadata[MetadataParentID])
+		assert.Len(t, doc.Metadata[MetadataContentHash], 64)
+
+		start, ok := doc.Metadata[MetadataStartIndex].(int)
+		require.True(t, ok)
+		end, ok := doc.Metadata[MetadataEndIndex].(int)
+		require.True(t, ok)
+		assert.Equal(t, doc.PageContent, string(runes[start:end]))
+	}
+
+	// the ids and hashes are stable
+	again, err := CreateDocuments(splitter, []string{text}, nil, WithChunkProvenance())
+	require.NoError(t, err)
+	for i := range docs {
+		assert.Equal(t, docs[i].Metadata[MetadataParentID], again[i].Metadata[MetadataParentID])
+		assert.Equal(t, docs[i].Metadata[MetadataContentHash], again[i].Metadata[MetadataContentHash])
+	}
+
+	// provenance is optional
+	plain, err := SplitDocuments(splitter, []schema.Document{{PageContent: text}})
+	require.NoError(t, err)
+	assert.NotContains(t, plain[0].Metadata, MetadataChunkIndex)
+}
+
+func TestSplitDocumentsParentIDFunc(t *testing.T) {
+	t.Parallel()
+
+	docs, err := CreateDocuments(
+		NewMarkdownTextSplitter(WithChunkSize(64), WithChunkOverlap(0)),
+		[]string{"# Title\n\nSome text.\n\n## Section\n\nMore text."},
+		[]map[string]any{{"source": "doc.md"}},
+		WithChunkProvenance(),
+		WithParentIDFunc(func(_ string, metadata map[string]any) string {
+			source, _ := metadata["source"].(string)
+			return source
+		}),
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, docs)
+	for _, doc := range docs {
+		assert.Equal(t, "doc.md", doc.Metadata[MetadataParentID])
+	}
+}
@@ -4,3 +4,12 @@ package textsplitter
 type TextSplitter interface {
 	SplitText(text string) ([]string, error)
 }
+
+// MetadataSplitter is a text splitter that also returns metadata for every chunk,
+// such as the symbol a chunk of code defines. CreateDocuments and SplitDocuments add
+// the metadata of a chunk to the metadata of its document.
+type MetadataSplitter interface {
+	TextSplitter
+	// SplitTextWithMetadata splits a text and returns the metadata of every chunk.
+	SplitTextWithMetadata(text string) ([]string, []map[string]any, error)
+}
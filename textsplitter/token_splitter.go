@@ -1,9 +1,10 @@
 package textsplitter
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/pkoukk/tiktoken-go"
+	"github.com/tmc/langchaingo/llms"
 )
 
 const (
@@ -14,12 +15,19 @@ const (
 	_defaultTokenChunkOverlap = 100
 )
 
-// TokenSplitter is a text splitter that will split texts by tokens.
+// ErrNoTokenEncoder is returned when the tokenizer of the model of a token splitter
+// can only count tokens, and not encode texts into tokens.
+var ErrNoTokenEncoder = errors.New("tokenizer cannot encode tokens")
+
+// TokenSplitter is a text splitter that will split texts by tokens. The tokens are
+// those of the Tokenizer, if set, or else of the tiktoken encoding EncodingName,
+// or else of the tokenizer registered for ModelName with llms.RegisterTokenizer.
 type TokenSplitter struct {
 	ChunkSize         int
 	ChunkOverlap      int
 	ModelName         string
 	EncodingName      string
+	Tokenizer         llms.TokenEncoder
 	AllowedSpecial    []string
 	DisallowedSpecial []string
 }
@@ -35,6 +43,7 @@ func NewTokenSplitter(opts ...Option) TokenSplitter {
 		ChunkOverlap:      options.ChunkOverlap,
 		ModelName:         options.ModelName,
 		EncodingName:      options.EncodingName,
+		Tokenizer:         options.Tokenizer,
 		AllowedSpecial:    options.AllowedSpecial,
 		DisallowedSpecial: options.DisallowedSpecial,
 	}
@@ -44,25 +53,49 @@ func NewTokenSplitter(opts ...Option) TokenSplitter {
 
 // SplitText splits a text into multiple text.
 func (s TokenSplitter) SplitText(text string) ([]string, error) {
-	// Get the tokenizer
-	var tk *tiktoken.Tiktoken
-	var err error
-	if s.EncodingName != "" {
-		tk, err = tiktoken.GetEncoding(s.EncodingName)
-	} else {
-		tk, err = tiktoken.EncodingForModel(s.ModelName)
-	}
+	tk, err := s.tokenizer()
 	if err != nil {
-		return nil, fmt.Errorf("tiktoken.GetEncoding: %w", err)
+		return nil, err
 	}
 	texts := s.splitText(text, tk)
 
 	return texts, nil
 }
 
-func (s TokenSplitter) splitText(text string, tk *tiktoken.Tiktoken) []string {
+// tokenizer returns the tokenizer texts are split with.
+func (s TokenSplitter) tokenizer() (llms.TokenEncoder, error) {
+	if s.Tokenizer != nil {
+		return s.Tokenizer, nil
+	}
+
+	var tokenizer llms.Tokenizer
+	if s.EncodingName != "" {
+		tk, err := llms.NewTiktokenTokenizer(s.EncodingName)
+		if err != nil {
+			return nil, fmt.Errorf("tiktoken.GetEncoding: %w", err)
+		}
+		tokenizer = tk
+	} else {
+		tokenizer = llms.GetTokenizer(s.ModelName)
+	}
+
+	// the special tokens are set on a copy, as registered tokenizers are shared.
+	if tk, ok := tokenizer.(*llms.TiktokenTokenizer); ok {
+		tk := *tk
+		tk.AllowedSpecial = s.AllowedSpecial
+		tk.DisallowedSpecial = s.DisallowedSpecial
+		return &tk, nil
+	}
+	encoder, ok := tokenizer.(llms.TokenEncoder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoTokenEncoder, s.ModelName)
+	}
+	return encoder, nil
+}
+
+func (s TokenSplitter) splitText(text string, tk llms.TokenEncoder) []string {
 	splits := make([]string, 0)
-	inputIDs := tk.Encode(text, s.AllowedSpecial, s.DisallowedSpecial)
+	inputIDs := tk.Encode(text)
 
 	startIdx := 0
 	curIdx := len(inputIDs)
@@ -1,10 +1,12 @@
 package textsplitter
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -86,3 +88,50 @@ Bye!
 		assert.Equal(t, tc.expectedDocs, docs)
 	}
 }
+
+// wordEncoder is a tokenizer whose tokens are the words of texts.
+type wordEncoder struct {
+	words []string
+}
+
+func (e *wordEncoder) CountTokens(text string) int {
+	return len(e.Encode(text))
+}
+
+func (e *wordEncoder) Encode(text string) []int {
+	tokens := make([]int, 0)
+	for _, word := range strings.Fields(text) {
+		tokens = append(tokens, len(e.words))
+		e.words = append(e.words, word)
+	}
+	return tokens
+}
+
+func (e *wordEncoder) Decode(tokens []int) string {
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, e.words[token])
+	}
+	return strings.Join(words, " ")
+}
+
+func TestTokenSplitterTokenizer(t *testing.T) {
+	t.Parallel()
+
+	splitter := NewTokenSplitter(
+		WithTokenizer(&wordEncoder{}),
+		WithChunkSize(3),
+		WithChunkOverlap(1),
+	)
+	chunks, err := splitter.SplitText("one two three four five six")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one two three", "three four five", "five six"}, chunks)
+
+	// models registered with a tokenizer that only counts tokens cannot be split.
+	llms.RegisterTokenizer("test-count-only-", func(string) (llms.Tokenizer, error) {
+		return llms.ApproximateTokenizer{}, nil
+	})
+	splitter = NewTokenSplitter(WithEncodingName(""), WithModelName("test-count-only-model"))
+	_, err = splitter.SplitText("one two three")
+	require.ErrorIs(t, err, ErrNoTokenEncoder)
+}
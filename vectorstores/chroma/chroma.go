@@ -20,6 +20,7 @@ var (
 	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
 	ErrNewClient                = errors.New("error creating collection")
 	ErrAddDocument              = errors.New("error adding document")
+	ErrDeleteDocument           = errors.New("error deleting document")
 	ErrRemoveCollection         = errors.New("error resetting collection")
 	ErrUnsupportedOptions       = errors.New("unsupported options")
 )
@@ -39,7 +40,10 @@ type Store struct {
 	includes     []chromatypes.QueryEnum
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+)
 
 // New creates an active client connection to the (specified, or default) collection in the Chroma server
 // and returns the `Store` object needed by the other accessors.
@@ -123,6 +127,17 @@ func (s Store) AddDocuments(ctx context.Context,
 	return ids, nil
 }
 
+// Delete removes the documents with the ids from the Chroma collection associated with 'Store'.
+func (s Store) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := s.collection.Delete(ctx, ids, nil, nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocument, err)
+	}
+	return nil
+}
+
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
@@ -0,0 +1,88 @@
+package chroma
+
+import (
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var _ vectorstores.FilterTranslator = TranslateFilter
+
+// nolint:gochecknoglobals
+var (
+	chromaComparators = map[vectorstores.Comparator]string{
+		vectorstores.ComparatorEq:  "$eq",
+		vectorstores.ComparatorNe:  "$ne",
+		vectorstores.ComparatorGt:  "$gt",
+		vectorstores.ComparatorGte: "$gte",
+		vectorstores.ComparatorLt:  "$lt",
+		vectorstores.ComparatorLte: "$lte",
+		vectorstores.ComparatorIn:  "$in",
+		vectorstores.ComparatorNin: "$nin",
+	}
+	negatedComparators = map[vectorstores.Comparator]vectorstores.Comparator{
+		vectorstores.ComparatorEq:  vectorstores.ComparatorNe,
+		vectorstores.ComparatorNe:  vectorstores.ComparatorEq,
+		vectorstores.ComparatorGt:  vectorstores.ComparatorLte,
+		vectorstores.ComparatorGte: vectorstores.ComparatorLt,
+		vectorstores.ComparatorLt:  vectorstores.ComparatorGte,
+		vectorstores.ComparatorLte: vectorstores.ComparatorGt,
+		vectorstores.ComparatorIn:  vectorstores.ComparatorNin,
+		vectorstores.ComparatorNin: vectorstores.ComparatorIn,
+	}
+)
+
+// TranslateFilter translates a filter to a Chroma where filter, such as
+// {"$and": [{"year": {"$gte": 2023}}, {"topic": {"$eq": "pricing"}}]}. Chroma
+// has no negation, so negated filters are rewritten with the opposite
+// comparators. Chroma only compares the order of numbers, so the dates of gt, gte,
+// lt and lte comparisons, in the RFC 3339 format or ISO 8601 dates such as
+// "2023-01-01", are compared as Unix timestamps in seconds, which the metadata
+// must hold.
+func TranslateFilter(filter vectorstores.Filter) (any, error) {
+	if err := vectorstores.ValidateFilter(filter); err != nil {
+		return nil, err
+	}
+	return whereFilter(filter, false), nil
+}
+
+func whereFilter(filter vectorstores.Filter, negate bool) map[string]any {
+	switch f := filter.(type) {
+	case vectorstores.Comparison:
+		comparator := f.Comparator
+		if negate {
+			comparator = negatedComparators[comparator]
+		}
+		value := f.Value
+		if date, ok := vectorstores.ParseDate(value); ok && isRangeComparator(comparator) {
+			value = date.Unix()
+		}
+		return map[string]any{f.Attribute: map[string]any{chromaComparators[comparator]: value}}
+	case vectorstores.Operation:
+		if f.Operator == vectorstores.OperatorNot {
+			return whereFilter(f.Arguments[0], !negate)
+		}
+		if len(f.Arguments) == 1 {
+			return whereFilter(f.Arguments[0], negate)
+		}
+		// by De Morgan's laws, the negation of a conjunction is the disjunction of
+		// the negations and the other way around.
+		operator := "$and"
+		if (f.Operator == vectorstores.OperatorOr) != negate {
+			operator = "$or"
+		}
+		operands := make([]map[string]any, len(f.Arguments))
+		for i, argument := range f.Arguments {
+			operands[i] = whereFilter(argument, negate)
+		}
+		return map[string]any{operator: operands}
+	}
+	return nil
+}
+
+func isRangeComparator(comparator vectorstores.Comparator) bool {
+	switch comparator {
+	case vectorstores.ComparatorGt, vectorstores.ComparatorGte, vectorstores.ComparatorLt, vectorstores.ComparatorLte:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+package chroma_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/chroma"
+)
+
+func TestTranslateFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := chroma.TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorAnd,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorGte, Attribute: "year", Value: 2023},
+			vectorstores.Operation{Operator: vectorstores.OperatorNot, Arguments: []vectorstores.Filter{
+				vectorstores.Operation{Operator: vectorstores.OperatorOr, Arguments: []vectorstores.Filter{
+					vectorstores.Comparison{Comparator: vectorstores.ComparatorEq, Attribute: "topic", Value: "hr"},
+					vectorstores.Comparison{Comparator: vectorstores.ComparatorIn, Attribute: "team", Value: []any{"a", "b"}},
+				}},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"$and": []map[string]any{
+		{"year": map[string]any{"$gte": 2023}},
+		{"$and": []map[string]any{
+			{"topic": map[string]any{"$ne": "hr"}},
+			{"team": map[string]any{"$nin": []any{"a", "b"}}},
+		}},
+	}}, filter)
+
+	// dates are compared as Unix timestamps
+	filter, err = chroma.TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorAnd,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorGte, Attribute: "date", Value: "2023-01-01"},
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorLt, Attribute: "date", Value: "2024-01-01T00:00:00Z"},
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorEq, Attribute: "day", Value: "2023-06-01"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"$and": []map[string]any{
+		{"date": map[string]any{"$gte": int64(1672531200)}},
+		{"date": map[string]any{"$lt": int64(1704067200)}},
+		{"day": map[string]any{"$eq": "2023-06-01"}},
+	}}, filter)
+
+	_, err = chroma.TranslateFilter(vectorstores.Comparison{Comparator: vectorstores.ComparatorIn, Attribute: "team", Value: "a"})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}
@@ -6,7 +6,10 @@ The main components of this package are:
 
 - VectorStore interface: a common interface for saving and querying vector embeddings of documents.
 - Options: a set of options for similarity search and document addition.
+- Deleter interface: implemented by vector stores that can delete documents by id.
 - Retriever: a retriever for vector stores that implements the schema.Retriever interface.
+- Filter: a metadata filter independent of the vector stores, which vector stores
+translate to their own filters with a FilterTranslator.
 
 The package provides a flexible way to handle different types of vector stores
 by using the VectorStore interface as an abstraction.
@@ -0,0 +1,147 @@
+package vectorstores
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnsupportedFilter is returned when a filter cannot be translated to the
+// filters of a vector store.
+var ErrUnsupportedFilter = errors.New("unsupported filter")
+
+// Comparator compares the value of a metadata attribute in a Comparison.
+type Comparator string
+
+const (
+	// ComparatorEq matches the documents whose attribute equals the value.
+	ComparatorEq Comparator = "eq"
+	// ComparatorNe matches the documents whose attribute does not equal the value.
+	ComparatorNe Comparator = "ne"
+	// ComparatorGt matches the documents whose attribute is greater than the value.
+	ComparatorGt Comparator = "gt"
+	// ComparatorGte matches the documents whose attribute is greater than or equal
+	// to the value.
+	ComparatorGte Comparator = "gte"
+	// ComparatorLt matches the documents whose attribute is less than the value.
+	ComparatorLt Comparator = "lt"
+	// ComparatorLte matches the documents whose attribute is less than or equal to
+	// the value.
+	ComparatorLte Comparator = "lte"
+	// ComparatorIn matches the documents whose attribute is one of the values of a
+	// list.
+	ComparatorIn Comparator = "in"
+	// ComparatorNin matches the documents whose attribute is none of the values of
+	// a list.
+	ComparatorNin Comparator = "nin"
+)
+
+// Operator combines the filters of an Operation.
+type Operator string
+
+const (
+	// OperatorAnd matches the documents matching all the filters.
+	OperatorAnd Operator = "and"
+	// OperatorOr matches the documents matching any of the filters.
+	OperatorOr Operator = "or"
+	// OperatorNot matches the documents not matching its single filter.
+	OperatorNot Operator = "not"
+)
+
+// Filter is a metadata filter independent of the vector stores, either a
+// Comparison or an Operation. Vector stores provide a FilterTranslator turning
+// it into the filters they accept with WithFilters.
+type Filter interface {
+	isFilter()
+}
+
+// Comparison is a filter comparing the value of a metadata attribute.
+type Comparison struct {
+	Comparator Comparator
+	Attribute  string
+	// Value is a string, number or bool, or a []any of them for ComparatorIn and
+	// ComparatorNin. Dates are strings in the RFC 3339 format, or ISO 8601 dates
+	// such as "2023-01-01"; see ParseDate.
+	Value any
+}
+
+// Operation is a filter combining filters.
+type Operation struct {
+	Operator  Operator
+	Arguments []Filter
+}
+
+func (Comparison) isFilter() {}
+func (Operation) isFilter()  {}
+
+// FilterTranslator translates a filter to the filters of a vector store, to be
+// used with WithFilters.
+type FilterTranslator func(filter Filter) (any, error)
+
+// ValidateFilter checks that the comparators and operators of a filter are known,
+// that ComparatorIn and ComparatorNin compare with lists and that OperatorNot has
+// a single argument.
+func ValidateFilter(filter Filter) error {
+	switch f := filter.(type) {
+	case Comparison:
+		return validateComparison(f)
+	case Operation:
+		switch f.Operator {
+		case OperatorAnd, OperatorOr:
+			if len(f.Arguments) == 0 {
+				return fmt.Errorf("%w: %s without arguments", ErrUnsupportedFilter, f.Operator)
+			}
+		case OperatorNot:
+			if len(f.Arguments) != 1 {
+				return fmt.Errorf("%w: not with %d arguments", ErrUnsupportedFilter, len(f.Arguments))
+			}
+		default:
+			return fmt.Errorf("%w: operator %q", ErrUnsupportedFilter, f.Operator)
+		}
+		for _, argument := range f.Arguments {
+			if err := ValidateFilter(argument); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedFilter, filter)
+	}
+}
+
+func validateComparison(c Comparison) error {
+	if c.Attribute == "" {
+		return fmt.Errorf("%w: comparison without attribute", ErrUnsupportedFilter)
+	}
+	_, isList := c.Value.([]any)
+	switch c.Comparator {
+	case ComparatorEq, ComparatorNe, ComparatorGt, ComparatorGte, ComparatorLt, ComparatorLte:
+		if isList {
+			return fmt.Errorf("%w: %s with a list", ErrUnsupportedFilter, c.Comparator)
+		}
+	case ComparatorIn, ComparatorNin:
+		if !isList {
+			return fmt.Errorf("%w: %s without a list", ErrUnsupportedFilter, c.Comparator)
+		}
+	default:
+		return fmt.Errorf("%w: comparator %q", ErrUnsupportedFilter, c.Comparator)
+	}
+	return nil
+}
+
+// ParseDate returns the time of a filter value that is a date: a string in the RFC
+// 3339 format, or an ISO 8601 date such as "2023-01-01", which is midnight UTC.
+// Translators use it for the stores that compare dates as dates or numbers.
+func ParseDate(value any) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	if date, err := time.Parse(time.RFC3339, s); err == nil {
+		return date, true
+	}
+	if date, err := time.Parse(time.DateOnly, s); err == nil {
+		return date, true
+	}
+	return time.Time{}, false
+}
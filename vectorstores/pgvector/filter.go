@@ -0,0 +1,129 @@
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var _ vectorstores.FilterTranslator = TranslateFilter
+
+// nolint:gochecknoglobals
+var sqlComparators = map[vectorstores.Comparator]string{
+	vectorstores.ComparatorEq:  "=",
+	vectorstores.ComparatorNe:  "<>",
+	vectorstores.ComparatorGt:  ">",
+	vectorstores.ComparatorGte: ">=",
+	vectorstores.ComparatorLt:  "<",
+	vectorstores.ComparatorLte: "<=",
+}
+
+// TranslateFilter checks that a filter can be used with the store and returns
+// it: besides maps of metadata values, the store accepts a vectorstores.Filter
+// with WithFilters, and turns it into SQL conditions on the metadata with bind
+// parameters. Values are compared as JSONB values, so numbers are compared as
+// numbers and strings as strings, except dates, in the RFC 3339 format or ISO 8601
+// dates such as "2023-01-01", which are compared as timestamps.
+func TranslateFilter(filter vectorstores.Filter) (any, error) {
+	if err := vectorstores.ValidateFilter(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// whereConditions returns the SQL conditions of the filters of the options on the
+// metadata column, and the arguments of their bind parameters, numbered from
+// firstParam.
+func (s Store) whereConditions(opts vectorstores.Options, column string, firstParam int) ([]string, []any, error) {
+	switch filters := opts.Filters.(type) {
+	case nil:
+		return []string{}, []any{}, nil
+	case map[string]any:
+		conditions := make([]string, 0, len(filters))
+		for k, v := range filters {
+			conditions = append(conditions, fmt.Sprintf("(%s ->> '%s') = '%s'", column, k, v))
+		}
+		return conditions, []any{}, nil
+	case vectorstores.Filter:
+		if err := vectorstores.ValidateFilter(filters); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFilters, err)
+		}
+		b := conditionBuilder{column: column, firstParam: firstParam}
+		condition, err := b.build(filters)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []string{condition}, b.args, nil
+	default:
+		return nil, nil, ErrInvalidFilters
+	}
+}
+
+// conditionBuilder builds the SQL condition of a filter.
+type conditionBuilder struct {
+	column     string
+	firstParam int
+	args       []any
+}
+
+// param adds the argument of a bind parameter and returns the parameter.
+func (b *conditionBuilder) param(arg any) string {
+	b.args = append(b.args, arg)
+	return fmt.Sprintf("$%d", b.firstParam+len(b.args)-1)
+}
+
+func (b *conditionBuilder) build(filter vectorstores.Filter) (string, error) {
+	switch f := filter.(type) {
+	case vectorstores.Comparison:
+		if date, ok := vectorstores.ParseDate(f.Value); ok && f.Comparator != vectorstores.ComparatorIn &&
+			f.Comparator != vectorstores.ComparatorNin {
+			return b.dateCondition(f, date), nil
+		}
+		jsonValue, err := json.Marshal(f.Value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidFilters, err)
+		}
+		attribute := fmt.Sprintf("%s::jsonb -> %s::text", b.column, b.param(f.Attribute))
+		value := b.param(string(jsonValue)) + "::jsonb"
+		switch f.Comparator {
+		case vectorstores.ComparatorIn:
+			return fmt.Sprintf("(%s @> jsonb_build_array(%s))", value, attribute), nil
+		case vectorstores.ComparatorNin:
+			return fmt.Sprintf("(NOT %s @> jsonb_build_array(%s))", value, attribute), nil
+		default:
+			return fmt.Sprintf("((%s) %s %s)", attribute, sqlComparators[f.Comparator], value), nil
+		}
+	case vectorstores.Operation:
+		conditions := make([]string, len(f.Arguments))
+		for i, argument := range f.Arguments {
+			condition, err := b.build(argument)
+			if err != nil {
+				return "", err
+			}
+			conditions[i] = condition
+		}
+		switch f.Operator {
+		case vectorstores.OperatorNot:
+			return fmt.Sprintf("(NOT %s)", conditions[0]), nil
+		case vectorstores.OperatorOr:
+			return "(" + strings.Join(conditions, " OR ") + ")", nil
+		default:
+			return "(" + strings.Join(conditions, " AND ") + ")", nil
+		}
+	}
+	return "", fmt.Errorf("%w: %T", ErrInvalidFilters, filter)
+}
+
+// dateCondition returns the condition of a comparison with a date, comparing the
+// attribute as a timestamptz. Stored ISO 8601 dates, such as "2023-01-01", are
+// midnight UTC, like in vectorstores.ParseDate.
+func (b *conditionBuilder) dateCondition(c vectorstores.Comparison, date time.Time) string {
+	attribute := fmt.Sprintf("(%s::jsonb ->> %s::text)", b.column, b.param(c.Attribute))
+	storedDate := fmt.Sprintf("(CASE WHEN length(%[1]s) = 10 THEN %[1]s || 'T00:00:00Z' ELSE %[1]s END)::timestamptz",
+		attribute)
+	value := b.param(date.Format(time.RFC3339Nano)) + "::timestamptz"
+	return fmt.Sprintf("(%s %s %s)", storedDate, sqlComparators[c.Comparator], value)
+}
@@ -0,0 +1,58 @@
+package pgvector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+func TestWhereConditions(t *testing.T) {
+	t.Parallel()
+
+	filter, err := TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorAnd,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorGte, Attribute: "date", Value: "2023-01-01"},
+			vectorstores.Operation{Operator: vectorstores.OperatorNot, Arguments: []vectorstores.Filter{
+				vectorstores.Comparison{Comparator: vectorstores.ComparatorIn, Attribute: "topic", Value: []any{"hr", "legal"}},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	conditions, args, err := Store{}.whereConditions(vectorstores.Options{Filters: filter}, "data.cmetadata", 4)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"(((CASE WHEN length((data.cmetadata::jsonb ->> $4::text)) = 10 " +
+			"THEN (data.cmetadata::jsonb ->> $4::text) || 'T00:00:00Z' " +
+			"ELSE (data.cmetadata::jsonb ->> $4::text) END)::timestamptz >= $5::timestamptz) AND " +
+			"(NOT ($7::jsonb @> jsonb_build_array(data.cmetadata::jsonb -> $6::text))))",
+	}, conditions)
+	require.Equal(t, []any{"date", "2023-01-01T00:00:00Z", "topic", `["hr","legal"]`}, args)
+
+	// stored ISO 8601 dates are compared as timestamps with RFC 3339 bounds
+	filter, err = TranslateFilter(vectorstores.Comparison{
+		Comparator: vectorstores.ComparatorLt, Attribute: "date", Value: "2023-01-01T12:30:00+02:00",
+	})
+	require.NoError(t, err)
+	conditions, args, err = Store{}.whereConditions(vectorstores.Options{Filters: filter}, "data.cmetadata", 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"((CASE WHEN length((data.cmetadata::jsonb ->> $1::text)) = 10 " +
+			"THEN (data.cmetadata::jsonb ->> $1::text) || 'T00:00:00Z' " +
+			"ELSE (data.cmetadata::jsonb ->> $1::text) END)::timestamptz < $2::timestamptz)",
+	}, conditions)
+	require.Equal(t, []any{"date", "2023-01-01T12:30:00+02:00"}, args)
+
+	conditions, args, err = Store{}.whereConditions(
+		vectorstores.Options{Filters: map[string]any{"location": "patio"}}, "data.cmetadata", 4)
+	require.NoError(t, err)
+	require.Equal(t, []string{"(data.cmetadata ->> 'location') = 'patio'"}, conditions)
+	require.Empty(t, args)
+
+	_, err = TranslateFilter(vectorstores.Comparison{Comparator: "like", Attribute: "topic", Value: "pric%"})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, _, err = Store{}.whereConditions(vectorstores.Options{Filters: "topic = 'pricing'"}, "data.cmetadata", 4)
+	require.ErrorIs(t, err, ErrInvalidFilters)
+}
@@ -71,7 +71,10 @@ type HNSWIndex struct {
 	distanceFunction string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.Deleter     = Store{}
+)
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (Store, error) {
@@ -268,6 +271,17 @@ func (s Store) AddDocuments(
 	return ids, s.conn.SendBatch(ctx, b).Close()
 }
 
+// Delete removes the documents with the ids from the Postgres collection associated
+// with 'Store'.
+func (s Store) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE collection_id = $1 AND uuid = ANY($2)`, s.embeddingTableName)
+	_, err := s.conn.Exec(ctx, sql, s.collectionUUID, ids)
+	return err
+}
+
 //nolint:cyclop
 func (s Store) SimilaritySearch(
 	ctx context.Context,
@@ -281,7 +295,7 @@ func (s Store) SimilaritySearch(
 	if err != nil {
 		return nil, err
 	}
-	filter, err := s.getFilters(opts)
+	whereQuerys, args, err := s.whereConditions(opts, "data.cmetadata", 4)
 	if err != nil {
 		return nil, err
 	}
@@ -293,13 +307,9 @@ func (s Store) SimilaritySearch(
 	if err != nil {
 		return nil, err
 	}
-	whereQuerys := make([]string, 0)
 	if scoreThreshold != 0 {
 		whereQuerys = append(whereQuerys, fmt.Sprintf("data.distance < %f", 1-scoreThreshold))
 	}
-	for k, v := range filter {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("(data.cmetadata ->> '%s') = '%s'", k, v))
-	}
 	whereQuery := strings.Join(whereQuerys, " AND ")
 	if len(whereQuery) == 0 {
 		whereQuery = "TRUE"
@@ -332,7 +342,7 @@ ORDER BY
 LIMIT $3`, s.embeddingTableName,
 		s.collectionTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, dims, pgvector.NewVector(embedderData), numDocuments)
+	rows, err := s.conn.Query(ctx, sql, append([]any{dims, pgvector.NewVector(embedderData), numDocuments}, args...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -357,14 +367,10 @@ func (s Store) Search(
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
 	collectionName := s.getNameSpace(opts)
-	filter, err := s.getFilters(opts)
+	whereQuerys, args, err := s.whereConditions(opts, s.embeddingTableName+".cmetadata", 2)
 	if err != nil {
 		return nil, err
 	}
-	whereQuerys := make([]string, 0)
-	for k, v := range filter {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("(%s.cmetadata ->> '%s') = '%s'", s.embeddingTableName, k, v))
-	}
 	whereQuery := strings.Join(whereQuerys, " AND ")
 	if len(whereQuery) == 0 {
 		whereQuery = "TRUE"
@@ -378,7 +384,7 @@ WHERE %s.name='%s' AND %s
 LIMIT $1`, s.embeddingTableName, s.embeddingTableName, s.embeddingTableName,
 		s.collectionTableName, s.embeddingTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, numDocuments)
+	rows, err := s.conn.Query(ctx, sql, append([]any{numDocuments}, args...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -445,18 +451,6 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-// getFilters return metadata filters, now only support map[key]value pattern
-// TODO: should support more types like {"key1": {"key2":"values2"}} or {"key": ["value1", "values2"]}.
-func (s Store) getFilters(opts vectorstores.Options) (map[string]any, error) {
-	if opts.Filters != nil {
-		if filters, ok := opts.Filters.(map[string]any); ok {
-			return filters, nil
-		}
-		return nil, ErrInvalidFilters
-	}
-	return map[string]any{}, nil
-}
-
 func (s Store) deduplicate(
 	ctx context.Context,
 	opts vectorstores.Options,
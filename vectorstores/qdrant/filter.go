@@ -0,0 +1,76 @@
+package qdrant
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var _ vectorstores.FilterTranslator = TranslateFilter
+
+// nolint:gochecknoglobals
+var rangeComparators = map[vectorstores.Comparator]string{
+	vectorstores.ComparatorGt:  "gt",
+	vectorstores.ComparatorGte: "gte",
+	vectorstores.ComparatorLt:  "lt",
+	vectorstores.ComparatorLte: "lte",
+}
+
+// TranslateFilter translates a filter to a Qdrant filter, such as
+// {"must": [{"key": "year", "range": {"gte": 2023}}]}. Comparisons of order use
+// range conditions, which compare numbers, and RFC 3339 datetimes on Qdrant 1.8
+// and later; ISO 8601 dates such as "2023-01-01" are compared as midnight UTC.
+func TranslateFilter(filter vectorstores.Filter) (any, error) {
+	if err := vectorstores.ValidateFilter(filter); err != nil {
+		return nil, err
+	}
+	if operation, ok := filter.(vectorstores.Operation); ok {
+		return operationFilter(operation), nil
+	}
+	return map[string]any{"must": []any{condition(filter)}}, nil
+}
+
+func operationFilter(operation vectorstores.Operation) map[string]any {
+	clause := "must"
+	switch operation.Operator {
+	case vectorstores.OperatorOr:
+		clause = "should"
+	case vectorstores.OperatorNot:
+		clause = "must_not"
+	case vectorstores.OperatorAnd:
+	}
+	conditions := make([]any, len(operation.Arguments))
+	for i, argument := range operation.Arguments {
+		conditions[i] = condition(argument)
+	}
+	return map[string]any{clause: conditions}
+}
+
+// condition returns the condition of a filter, which is a nested filter for
+// operations and for negated comparisons.
+func condition(filter vectorstores.Filter) map[string]any {
+	switch f := filter.(type) {
+	case vectorstores.Operation:
+		return operationFilter(f)
+	case vectorstores.Comparison:
+		switch f.Comparator {
+		case vectorstores.ComparatorEq:
+			return map[string]any{"key": f.Attribute, "match": map[string]any{"value": f.Value}}
+		case vectorstores.ComparatorNe:
+			return map[string]any{"must_not": []any{
+				map[string]any{"key": f.Attribute, "match": map[string]any{"value": f.Value}},
+			}}
+		case vectorstores.ComparatorIn:
+			return map[string]any{"key": f.Attribute, "match": map[string]any{"any": f.Value}}
+		case vectorstores.ComparatorNin:
+			return map[string]any{"key": f.Attribute, "match": map[string]any{"except": f.Value}}
+		case vectorstores.ComparatorGt, vectorstores.ComparatorGte, vectorstores.ComparatorLt, vectorstores.ComparatorLte:
+			value := f.Value
+			if date, ok := vectorstores.ParseDate(value); ok {
+				value = date.Format(time.RFC3339)
+			}
+			return map[string]any{"key": f.Attribute, "range": map[string]any{rangeComparators[f.Comparator]: value}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package qdrant_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/tmc/langchaingo/vectorstores/qdrant"
+)
+
+func TestTranslateFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := qdrant.TranslateFilter(vectorstores.Comparison{
+		Comparator: vectorstores.ComparatorEq, Attribute: "location", Value: "patio",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"must": []any{
+		map[string]any{"key": "location", "match": map[string]any{"value": "patio"}},
+	}}, filter)
+
+	filter, err = qdrant.TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorOr,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorLt, Attribute: "year", Value: 2020},
+			vectorstores.Operation{Operator: vectorstores.OperatorAnd, Arguments: []vectorstores.Filter{
+				vectorstores.Comparison{Comparator: vectorstores.ComparatorNe, Attribute: "topic", Value: "hr"},
+				vectorstores.Comparison{Comparator: vectorstores.ComparatorNin, Attribute: "team", Value: []any{"a"}},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"should": []any{
+		map[string]any{"key": "year", "range": map[string]any{"lt": 2020}},
+		map[string]any{"must": []any{
+			map[string]any{"must_not": []any{
+				map[string]any{"key": "topic", "match": map[string]any{"value": "hr"}},
+			}},
+			map[string]any{"key": "team", "match": map[string]any{"except": []any{"a"}}},
+		}},
+	}}, filter)
+
+	// dates are compared as RFC 3339 datetimes
+	filter, err = qdrant.TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorAnd,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorGte, Attribute: "date", Value: "2023-01-01"},
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorLt, Attribute: "date", Value: "2024-01-01T00:00:00Z"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"must": []any{
+		map[string]any{"key": "date", "range": map[string]any{"gte": "2023-01-01T00:00:00Z"}},
+		map[string]any{"key": "date", "range": map[string]any{"lt": "2024-01-01T00:00:00Z"}},
+	}}, filter)
+
+	_, err = qdrant.TranslateFilter(vectorstores.Operation{Operator: vectorstores.OperatorNot})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}
@@ -14,6 +14,12 @@ type VectorStore interface {
 	SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
 }
 
+// Deleter is implemented by vector stores that can delete the documents with the
+// ids AddDocuments returned.
+type Deleter interface {
+	Delete(ctx context.Context, ids []string) error
+}
+
 // Retriever is a retriever for vector stores.
 type Retriever struct {
 	CallbacksHandler callbacks.Handler
@@ -0,0 +1,131 @@
+package weaviate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+)
+
+var _ vectorstores.FilterTranslator = TranslateFilter
+
+// nolint:gochecknoglobals
+var (
+	whereOperators = map[vectorstores.Comparator]filters.WhereOperator{
+		vectorstores.ComparatorEq:  filters.Equal,
+		vectorstores.ComparatorNe:  filters.NotEqual,
+		vectorstores.ComparatorGt:  filters.GreaterThan,
+		vectorstores.ComparatorGte: filters.GreaterThanEqual,
+		vectorstores.ComparatorLt:  filters.LessThan,
+		vectorstores.ComparatorLte: filters.LessThanEqual,
+	}
+	negatedComparators = map[vectorstores.Comparator]vectorstores.Comparator{
+		vectorstores.ComparatorEq:  vectorstores.ComparatorNe,
+		vectorstores.ComparatorNe:  vectorstores.ComparatorEq,
+		vectorstores.ComparatorGt:  vectorstores.ComparatorLte,
+		vectorstores.ComparatorGte: vectorstores.ComparatorLt,
+		vectorstores.ComparatorLt:  vectorstores.ComparatorGte,
+		vectorstores.ComparatorLte: vectorstores.ComparatorGt,
+		vectorstores.ComparatorIn:  vectorstores.ComparatorNin,
+		vectorstores.ComparatorNin: vectorstores.ComparatorIn,
+	}
+)
+
+// TranslateFilter translates a filter to a *filters.WhereBuilder. Dates, in the
+// RFC 3339 format or ISO 8601 dates such as "2023-01-01", are compared as dates,
+// integers as ints and other numbers as numbers. Negated filters are rewritten
+// with the opposite operators, and lists of values are compared one value at a
+// time.
+func TranslateFilter(filter vectorstores.Filter) (any, error) {
+	if err := vectorstores.ValidateFilter(filter); err != nil {
+		return nil, err
+	}
+	where, err := whereBuilder(filter, false)
+	if err != nil {
+		return nil, err
+	}
+	return where, nil
+}
+
+func whereBuilder(filter vectorstores.Filter, negate bool) (*filters.WhereBuilder, error) {
+	switch f := filter.(type) {
+	case vectorstores.Comparison:
+		comparator := f.Comparator
+		if negate {
+			comparator = negatedComparators[comparator]
+		}
+		if comparator == vectorstores.ComparatorIn || comparator == vectorstores.ComparatorNin {
+			return listWhereBuilder(f.Attribute, comparator, f.Value.([]any)) //nolint:forcetypeassert
+		}
+		return withValue(filters.Where().WithPath([]string{f.Attribute}).WithOperator(whereOperators[comparator]), f.Value)
+	case vectorstores.Operation:
+		if f.Operator == vectorstores.OperatorNot {
+			return whereBuilder(f.Arguments[0], !negate)
+		}
+		operator := filters.And
+		if (f.Operator == vectorstores.OperatorOr) != negate {
+			operator = filters.Or
+		}
+		operands := make([]*filters.WhereBuilder, len(f.Arguments))
+		for i, argument := range f.Arguments {
+			operand, err := whereBuilder(argument, negate)
+			if err != nil {
+				return nil, err
+			}
+			operands[i] = operand
+		}
+		if len(operands) == 1 {
+			return operands[0], nil
+		}
+		return filters.Where().WithOperator(operator).WithOperands(operands), nil
+	}
+	return nil, fmt.Errorf("%w: %T", vectorstores.ErrUnsupportedFilter, filter)
+}
+
+// listWhereBuilder returns the disjunction of the equalities to the values for
+// ComparatorIn, and the conjunction of the inequalities for ComparatorNin.
+func listWhereBuilder(attribute string, comparator vectorstores.Comparator, values []any) (*filters.WhereBuilder, error) {
+	operator, elementOperator := filters.Or, filters.Equal
+	if comparator == vectorstores.ComparatorNin {
+		operator, elementOperator = filters.And, filters.NotEqual
+	}
+	operands := make([]*filters.WhereBuilder, 0, len(values))
+	for _, value := range values {
+		operand, err := withValue(filters.Where().WithPath([]string{attribute}).WithOperator(elementOperator), value)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	switch len(operands) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s with an empty list", vectorstores.ErrUnsupportedFilter, comparator)
+	case 1:
+		return operands[0], nil
+	}
+	return filters.Where().WithOperator(operator).WithOperands(operands), nil
+}
+
+func withValue(builder *filters.WhereBuilder, value any) (*filters.WhereBuilder, error) {
+	switch v := value.(type) {
+	case string:
+		if date, ok := vectorstores.ParseDate(v); ok {
+			return builder.WithValueDate(date), nil
+		}
+		return builder.WithValueString(v), nil
+	case time.Time:
+		return builder.WithValueDate(v), nil
+	case bool:
+		return builder.WithValueBoolean(v), nil
+	case int:
+		return builder.WithValueInt(int64(v)), nil
+	case int64:
+		return builder.WithValueInt(v), nil
+	case float64:
+		return builder.WithValueNumber(v), nil
+	case float32:
+		return builder.WithValueNumber(float64(v)), nil
+	}
+	return nil, fmt.Errorf("%w: value %v of type %T", vectorstores.ErrUnsupportedFilter, value, value)
+}
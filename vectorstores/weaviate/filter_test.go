@@ -0,0 +1,60 @@
+package weaviate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/vectorstores"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+)
+
+func TestTranslateFilter(t *testing.T) {
+	t.Parallel()
+
+	filter, err := TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorAnd,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorGte, Attribute: "date", Value: "2023-01-01T00:00:00Z"},
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorIn, Attribute: "topic", Value: []any{"pricing", "sales"}},
+			vectorstores.Operation{Operator: vectorstores.OperatorNot, Arguments: []vectorstores.Filter{
+				vectorstores.Comparison{Comparator: vectorstores.ComparatorGt, Attribute: "pages", Value: int64(10)},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	expected := filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+		filters.Where().WithPath([]string{"date"}).WithOperator(filters.GreaterThanEqual).WithValueDate(date),
+		filters.Where().WithOperator(filters.Or).WithOperands([]*filters.WhereBuilder{
+			filters.Where().WithPath([]string{"topic"}).WithOperator(filters.Equal).WithValueString("pricing"),
+			filters.Where().WithPath([]string{"topic"}).WithOperator(filters.Equal).WithValueString("sales"),
+		}),
+		filters.Where().WithPath([]string{"pages"}).WithOperator(filters.LessThanEqual).WithValueInt(10),
+	})
+	where, ok := filter.(*filters.WhereBuilder)
+	require.True(t, ok)
+	require.Equal(t, expected.Build(), where.Build())
+
+	// ISO 8601 dates are compared as dates
+	filter, err = TranslateFilter(vectorstores.Operation{
+		Operator: vectorstores.OperatorAnd,
+		Arguments: []vectorstores.Filter{
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorGte, Attribute: "date", Value: "2023-01-01"},
+			vectorstores.Comparison{Comparator: vectorstores.ComparatorLt, Attribute: "date", Value: "2024-01-01"},
+		},
+	})
+	require.NoError(t, err)
+	expected = filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+		filters.Where().WithPath([]string{"date"}).WithOperator(filters.GreaterThanEqual).WithValueDate(date),
+		filters.Where().WithPath([]string{"date"}).WithOperator(filters.LessThan).
+			WithValueDate(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	where, ok = filter.(*filters.WhereBuilder)
+	require.True(t, ok)
+	require.Equal(t, expected.Build(), where.Build())
+
+	_, err = TranslateFilter(vectorstores.Comparison{Comparator: vectorstores.ComparatorEq, Attribute: "tags", Value: map[string]any{}})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}